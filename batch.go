@@ -0,0 +1,331 @@
+package chromaclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// AddBatched splits req into batches of batchSize rows and adds them to a
+// collection one batch at a time. If batchSize is 0 the server's
+// max_batch_size from PreFlightChecks is used. Every batch is attempted; the
+// errors of failed batches are joined.
+func (c *Client) AddBatched(ctx context.Context, collectionID string, req AddEmbedding, batchSize int, tenant, database string) error {
+	if err := validateAddEmbedding(req); err != nil {
+		return err
+	}
+	req, err := resolveDuplicateIDs(c, req)
+	if err != nil {
+		return err
+	}
+	batchSize, err = c.resolveBatchSize(ctx, batchSize)
+	if err != nil {
+		return err
+	}
+	batches, err := splitAddEmbedding(req, batchSize)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, batch := range batches {
+		if err := c.Add(batchContext(ctx, i), collectionID, batch, tenant, database); err != nil {
+			errs = append(errs, fmt.Errorf("batch %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeleteBatched deletes the records with the given IDs in batches of
+// batchSize IDs, one batch at a time. If batchSize is 0 the server's
+// max_batch_size from PreFlightChecks is used. Every batch is attempted; the
+// errors of failed batches are joined, and no further batches are sent once
+// ctx is done. The server does not report which IDs existed, so the returned
+// slice lists the IDs of the batches it accepted, in order.
+func (c *Client) DeleteBatched(ctx context.Context, collectionID string, ids []string, batchSize int, tenant, database string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	batchSize, err := c.resolveBatchSize(ctx, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+
+	var (
+		deleted []string
+		errs    []error
+	)
+	for start := 0; start < len(ids); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		batch := ids[start:min(start+batchSize, len(ids))]
+		if err := c.Delete(ctx, collectionID, DeleteEmbedding{IDs: batch}, tenant, database); err != nil {
+			errs = append(errs, fmt.Errorf("batch %d: %w", start/batchSize, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		deleted = append(deleted, batch...)
+	}
+	return deleted, errors.Join(errs...)
+}
+
+// AddConcurrent splits req into batches of batchSize rows and adds them to a
+// collection using up to parallelism concurrent requests. If batchSize is 0
+// the server's max_batch_size from PreFlightChecks is used.
+//
+// Because batches are sent concurrently, the same ID appearing in two
+// different batches would leave the collection in a nondeterministic state.
+// Duplicate IDs are therefore resolved by the client's DuplicateIDPolicy
+// before req is split: by default AddConcurrent returns an error without
+// sending anything.
+func (c *Client) AddConcurrent(ctx context.Context, collectionID string, req AddEmbedding, batchSize, parallelism int, tenant, database string) error {
+	if err := validateAddEmbedding(req); err != nil {
+		return err
+	}
+	req, err := resolveDuplicateIDs(c, req)
+	if err != nil {
+		return err
+	}
+	batchSize, err = c.resolveBatchSize(ctx, batchSize)
+	if err != nil {
+		return err
+	}
+	batches, err := splitAddEmbedding(req, batchSize)
+	if err != nil {
+		return err
+	}
+
+	return runBatches(ctx, batches, parallelism, func(ctx context.Context, batch AddEmbedding) error {
+		return c.Add(ctx, collectionID, batch, tenant, database)
+	})
+}
+
+// UpsertConcurrent splits req into batches of batchSize rows and upserts them
+// into a collection using up to parallelism concurrent requests. If batchSize
+// is 0 the server's max_batch_size from PreFlightChecks is used.
+//
+// Duplicate IDs are resolved before the payload is split: only the last
+// occurrence of each ID is kept, so the final state matches what a single
+// sequential upsert of req would produce.
+func (c *Client) UpsertConcurrent(ctx context.Context, collectionID string, req AddEmbedding, batchSize, parallelism int, tenant, database string) error {
+	if err := validateAddEmbedding(req); err != nil {
+		return err
+	}
+	batchSize, err := c.resolveBatchSize(ctx, batchSize)
+	if err != nil {
+		return err
+	}
+	batches, err := splitAddEmbedding(req.dedupKeepLast(), batchSize)
+	if err != nil {
+		return err
+	}
+
+	return runBatches(ctx, batches, parallelism, func(ctx context.Context, batch AddEmbedding) error {
+		return c.Upsert(ctx, collectionID, batch, tenant, database)
+	})
+}
+
+// CollectionQuery is one query for QueryConcurrent. Empty Tenant and
+// Database use the client defaults.
+type CollectionQuery struct {
+	CollectionID string
+	Query        QueryEmbedding
+	Tenant       string
+	Database     string
+}
+
+// QueryConcurrentOption configures QueryConcurrent
+type QueryConcurrentOption func(*queryConcurrentConfig)
+
+type queryConcurrentConfig struct {
+	continueOnError bool
+}
+
+// WithContinueOnError makes QueryConcurrent run every query even after one
+// fails, instead of cancelling the rest
+func WithContinueOnError() QueryConcurrentOption {
+	return func(c *queryConcurrentConfig) {
+		c.continueOnError = true
+	}
+}
+
+// QueryConcurrent runs independent queries, possibly against different
+// collections, using up to parallelism concurrent requests. results[i] is the
+// result of reqs[i], or nil if that query failed or was skipped. By default
+// the first failure cancels the queries still pending; the returned error
+// joins the failures, each prefixed with its query index.
+func (c *Client) QueryConcurrent(ctx context.Context, reqs []CollectionQuery, parallelism int, opts ...QueryConcurrentOption) ([]*QueryResult, error) {
+	var cfg queryConcurrentConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	results := make([]*QueryResult, len(reqs))
+	err := runParallel(ctx, len(reqs), parallelism, !cfg.continueOnError, "query", func(ctx context.Context, i int) error {
+		q := reqs[i]
+		result, err := c.Query(ctx, q.CollectionID, q.Query, q.Tenant, q.Database)
+		if err != nil {
+			return err
+		}
+		results[i] = result
+		return nil
+	})
+	return results, err
+}
+
+// resolveBatchSize returns batchSize, or the server's max batch size when
+// batchSize is 0
+func (c *Client) resolveBatchSize(ctx context.Context, batchSize int) (int, error) {
+	if batchSize != 0 {
+		return batchSize, nil
+	}
+
+	return c.MaxBatchSize(ctx)
+}
+
+// runBatches calls fn for each batch with at most parallelism calls in flight.
+// Remaining batches are skipped once a call fails or ctx is cancelled. Each
+// call gets its own idempotency key derived from the one in ctx.
+func runBatches(ctx context.Context, batches []AddEmbedding, parallelism int, fn func(context.Context, AddEmbedding) error) error {
+	return runParallel(ctx, len(batches), parallelism, true, "batch", func(ctx context.Context, i int) error {
+		return fn(batchContext(ctx, i), batches[i])
+	})
+}
+
+// runParallel calls fn for 0 <= i < n with at most parallelism calls in
+// flight. If failFast is set, remaining calls are skipped once one fails;
+// they are always skipped once ctx is cancelled. Errors are prefixed with
+// label and the index and joined.
+func runParallel(ctx context.Context, n, parallelism int, failFast bool, label string, fn func(context.Context, int) error) error {
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, parallelism)
+	)
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(ctx, i); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s %d: %w", label, i, err))
+				mu.Unlock()
+				if failFast {
+					cancel()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(errs) == 0 {
+		return ctx.Err()
+	}
+	return errors.Join(errs...)
+}
+
+// splitAddEmbedding splits req into batches of at most size rows, keeping the
+// IDs, embeddings, metadatas, documents and URIs of each row together.
+func splitAddEmbedding(req AddEmbedding, size int) ([]AddEmbedding, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("batch size must be positive, got %d", size)
+	}
+
+	n := len(req.IDs)
+	batches := make([]AddEmbedding, 0, (n+size-1)/size)
+	for start := 0; start < n; start += size {
+		end := min(start+size, n)
+		batches = append(batches, AddEmbedding{
+			IDs:        req.IDs[start:end],
+			Embeddings: sliceRows(req.Embeddings, start, end),
+			Metadatas:  sliceRows(req.Metadatas, start, end),
+			Documents:  sliceRows(req.Documents, start, end),
+			Uris:       sliceRows(req.Uris, start, end),
+		})
+	}
+	return batches, nil
+}
+
+// sliceRows returns s[start:end], or nil when the optional column s was not
+// provided.
+func sliceRows[T any](s []T, start, end int) []T {
+	if len(s) == 0 {
+		return nil
+	}
+	return s[min(start, len(s)):min(end, len(s))]
+}
+
+// dedupKeepLast returns a copy of req in which each ID appears only once,
+// keeping the row of its last occurrence. Rows keep their original relative
+// order.
+func (req AddEmbedding) dedupKeepLast() AddEmbedding {
+	keep := lastOccurrences(req.IDs)
+	if keep == nil {
+		return req
+	}
+	return AddEmbedding{
+		IDs:        pick(req.IDs, keep),
+		Embeddings: pick(req.Embeddings, keep),
+		Metadatas:  pick(req.Metadatas, keep),
+		Documents:  pick(req.Documents, keep),
+		Uris:       pick(req.Uris, keep),
+	}
+}
+
+// lastOccurrences returns the positions of the last occurrence of each ID in
+// ascending order, or nil if ids has no duplicates
+func lastOccurrences(ids []string) []int {
+	last := make(map[string]int, len(ids))
+	for i, id := range ids {
+		last[id] = i
+	}
+	if len(last) == len(ids) {
+		return nil
+	}
+	keep := make([]int, 0, len(last))
+	for i, id := range ids {
+		if last[id] == i {
+			keep = append(keep, i)
+		}
+	}
+	return keep
+}
+
+// pick returns the entries of s at positions, skipping positions past the
+// end of a short or absent column
+func pick[T any](s []T, positions []int) []T {
+	var out []T
+	for _, i := range positions {
+		if i < len(s) {
+			out = append(out, s[i])
+		}
+	}
+	return out
+}
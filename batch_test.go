@@ -0,0 +1,350 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestUpsertConcurrentDedupAcrossBatches(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/upsert") {
+			t.Errorf("Expected upsert path, got %s", r.URL.Path)
+		}
+
+		var req AddEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+
+		mu.Lock()
+		for i, id := range req.IDs {
+			if _, ok := received[id]; ok {
+				t.Errorf("ID %s sent more than once", id)
+			}
+			received[id] = req.Documents[i]
+		}
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	// "a" appears in the first and last batch; the last occurrence must win.
+	err := client.UpsertConcurrent(context.Background(), "col-123", AddEmbedding{
+		IDs:       []string{"a", "b", "c", "d", "a"},
+		Documents: []string{"a-old", "b", "c", "d", "a-new"},
+	}, 2, 4, "", "")
+	if err != nil {
+		t.Fatalf("UpsertConcurrent() error = %v", err)
+	}
+
+	if len(received) != 4 {
+		t.Errorf("Expected 4 unique IDs, got %d", len(received))
+	}
+	if received["a"] != "a-new" {
+		t.Errorf("Expected last occurrence of a to win, got %s", received["a"])
+	}
+}
+
+func TestAddConcurrentRejectsCrossBatchDuplicates(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.AddConcurrent(context.Background(), "col-123", AddEmbedding{
+		IDs:       []string{"a", "b", "c", "a"},
+		Documents: []string{"1", "2", "3", "4"},
+	}, 2, 2, "", "")
+	if err == nil {
+		t.Fatal("Expected error for duplicate IDs across batches, got nil")
+	}
+	if !strings.Contains(err.Error(), `"a"`) {
+		t.Errorf("Expected error to name the duplicate id, got %v", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("Expected no requests to be sent, got %d", n)
+	}
+}
+
+func TestAddConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AddEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if len(req.IDs) > 2 {
+			t.Errorf("Expected at most 2 IDs per batch, got %d", len(req.IDs))
+		}
+		if len(req.Embeddings) != len(req.IDs) {
+			t.Errorf("Expected embeddings to stay aligned with IDs, got %d for %d IDs", len(req.Embeddings), len(req.IDs))
+		}
+
+		mu.Lock()
+		ids = append(ids, req.IDs...)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.AddConcurrent(context.Background(), "col-123", AddEmbedding{
+		IDs:        []string{"a", "b", "c", "d", "e"},
+		Embeddings: [][]float64{{1}, {2}, {3}, {4}, {5}},
+	}, 2, 3, "", "")
+	if err != nil {
+		t.Fatalf("AddConcurrent() error = %v", err)
+	}
+	if len(ids) != 5 {
+		t.Errorf("Expected 5 IDs to be added, got %d", len(ids))
+	}
+}
+
+func TestAddBatchedKeepsRowsTogether(t *testing.T) {
+	var batches []AddEmbedding
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AddEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		batches = append(batches, req)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.AddBatched(context.Background(), "col-123", AddEmbedding{
+		IDs:        []string{"a", "b", "c"},
+		Embeddings: [][]float64{{1}, {2}, {3}},
+		Documents:  []string{"doc-a", "doc-b", "doc-c"},
+		Metadatas:  []map[string]interface{}{{"n": "a"}, {"n": "b"}, {"n": "c"}},
+		Uris:       []string{"uri-a", "uri-b", "uri-c"},
+	}, 2, "", "")
+	if err != nil {
+		t.Fatalf("AddBatched() error = %v", err)
+	}
+
+	if len(batches) != 2 {
+		t.Fatalf("Expected 2 batches, got %d", len(batches))
+	}
+	last := batches[1]
+	if len(last.IDs) != 1 || last.IDs[0] != "c" {
+		t.Fatalf("Expected second batch to contain only c, got %v", last.IDs)
+	}
+	if last.Embeddings[0][0] != 3 || last.Documents[0] != "doc-c" || last.Metadatas[0]["n"] != "c" || last.Uris[0] != "uri-c" {
+		t.Errorf("Expected row c to stay together, got %+v", last)
+	}
+}
+
+func TestDeleteBatched(t *testing.T) {
+	var batches [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/pre-flight-checks" {
+			w.Write([]byte(`{"max_batch_size": 2}`))
+			return
+		}
+		var req DeleteEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		batches = append(batches, req.IDs)
+		if req.IDs[0] == "c" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	deleted, err := client.DeleteBatched(context.Background(), "col-123", []string{"a", "b", "c", "d", "e"}, 0, "", "")
+	if err == nil || !strings.Contains(err.Error(), "batch 1") {
+		t.Errorf("Expected the second batch to fail, got %v", err)
+	}
+	if fmt.Sprint(batches) != "[[a b] [c d] [e]]" {
+		t.Errorf("Expected batches of 2, got %v", batches)
+	}
+	if fmt.Sprint(deleted) != "[a b e]" {
+		t.Errorf("Expected IDs of accepted batches, got %v", deleted)
+	}
+}
+
+func TestDeleteBatchedStopsEarly(t *testing.T) {
+	var requests int
+	ctx, cancel := context.WithCancel(context.Background())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		cancel()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if deleted, err := client.DeleteBatched(context.Background(), "col-123", nil, 0, "", ""); deleted != nil || err != nil || requests != 0 {
+		t.Errorf("Expected no requests for no IDs, got %v, %v after %d requests", deleted, err, requests)
+	}
+
+	// The server cancels ctx while handling the first batch
+	_, err := client.DeleteBatched(ctx, "col-123", []string{"a", "b", "c", "d"}, 1, "", "")
+	if !errors.Is(err, context.Canceled) || strings.Count(err.Error(), "context canceled") != 1 {
+		t.Errorf("Expected a single cancellation error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected no batches after cancellation, got %d requests", requests)
+	}
+}
+
+func TestAddBatchedUsesServerMaxBatchSize(t *testing.T) {
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/pre-flight-checks" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"max_batch_size": 2}`))
+			return
+		}
+
+		var req AddEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		sizes = append(sizes, len(req.IDs))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.AddBatched(context.Background(), "col-123", AddEmbedding{
+		IDs:       []string{"a", "b", "c", "d", "e"},
+		Documents: []string{"1", "2", "3", "4", "5"},
+	}, 0, "", "")
+	if err != nil {
+		t.Fatalf("AddBatched() error = %v", err)
+	}
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[2] != 1 {
+		t.Errorf("Expected batch sizes [2 2 1], got %v", sizes)
+	}
+}
+
+func TestAddBatchedAggregatesErrors(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("boom"))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.AddBatched(context.Background(), "col-123", AddEmbedding{
+		IDs:       []string{"a", "b", "c"},
+		Documents: []string{"1", "2", "3"},
+	}, 1, "", "")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "batch 0") {
+		t.Errorf("Expected error to name the failed batch, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected all 3 batches to be attempted, got %d", requests)
+	}
+}
+
+// newQueryServer answers each query with the collection ID as the only hit,
+// failing queries against collection "bad"
+func newQueryServer(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		parts := strings.Split(r.URL.Path, "/")
+		collectionID := parts[len(parts)-2]
+		if collectionID == "bad" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(QueryResult{IDs: [][]string{{collectionID}}})
+	}))
+}
+
+func collectionQueries(ids ...string) []CollectionQuery {
+	reqs := make([]CollectionQuery, len(ids))
+	for i, id := range ids {
+		reqs[i] = CollectionQuery{CollectionID: id, Query: QueryEmbedding{QueryEmbeddings: [][]float64{{0.1}}}}
+	}
+	return reqs
+}
+
+func TestQueryConcurrentPreservesOrder(t *testing.T) {
+	var calls atomic.Int32
+	server := newQueryServer(t, &calls)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	results, err := client.QueryConcurrent(context.Background(), collectionQueries("c0", "c1", "c2", "c3", "c4"), 3)
+	if err != nil {
+		t.Fatalf("QueryConcurrent() error = %v", err)
+	}
+	for i, result := range results {
+		if want := fmt.Sprintf("c%d", i); result == nil || result.IDs[0][0] != want {
+			t.Errorf("Result %d: expected %s, got %+v", i, want, result)
+		}
+	}
+}
+
+func TestQueryConcurrentFailFast(t *testing.T) {
+	var calls atomic.Int32
+	server := newQueryServer(t, &calls)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	results, err := client.QueryConcurrent(context.Background(), collectionQueries("bad", "c1", "c2"), 1)
+	if err == nil || !strings.Contains(err.Error(), "query 0") {
+		t.Fatalf("Expected error for query 0, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected remaining queries to be skipped, got %d calls", calls.Load())
+	}
+	if results[1] != nil || results[2] != nil {
+		t.Errorf("Expected skipped queries to have nil results, got %+v", results)
+	}
+}
+
+func TestQueryConcurrentContinueOnError(t *testing.T) {
+	var calls atomic.Int32
+	server := newQueryServer(t, &calls)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	results, err := client.QueryConcurrent(context.Background(), collectionQueries("c0", "bad", "c2"), 1, WithContinueOnError())
+	if err == nil || !strings.Contains(err.Error(), "query 1") {
+		t.Fatalf("Expected error for query 1, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected all 3 queries to run, got %d", calls.Load())
+	}
+	if results[0] == nil || results[1] != nil || results[2] == nil {
+		t.Errorf("Expected results for queries 0 and 2 only, got %+v", results)
+	}
+}
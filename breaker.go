@@ -0,0 +1,100 @@
+package chromaclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the server while the circuit
+// breaker set with WithCircuitBreaker is open
+var ErrCircuitOpen = errors.New("chroma circuit breaker is open")
+
+// WithCircuitBreaker makes the client fail fast while the server is down.
+// After failureThreshold consecutive failures, meaning connection errors and
+// 5xx responses, requests return ErrCircuitOpen immediately for cooldown.
+// The next request after that is let through as a probe: success closes the
+// circuit, failure opens it for another cooldown. Timeouts count as
+// failures, whether they come from WithTimeout or the caller's own deadline;
+// requests the caller cancels do not count. Requests rejected with
+// ErrCircuitOpen are not logged, traced or reported to Metrics.
+func WithCircuitBreaker(failureThreshold int, cooldown time.Duration) ClientOption {
+	return func(c *Client) {
+		if failureThreshold <= 0 || cooldown <= 0 {
+			c.addErr(fmt.Errorf("invalid circuit breaker: threshold and cooldown must be positive"))
+			return
+		}
+		c.breaker = &circuitBreaker{threshold: failureThreshold, cooldown: cooldown, now: time.Now}
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker tracks consecutive request failures. It is safe for
+// concurrent use.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow reports whether a request may be sent. In the half-open state only
+// one probe is let through at a time.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+	case breakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// done records the outcome of a request let through by allow
+func (b *circuitBreaker) done(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !failed {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// abort releases a request let through by allow without recording an
+// outcome, when the caller cancelled it
+func (b *circuitBreaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
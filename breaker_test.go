@@ -0,0 +1,155 @@
+package chromaclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		healthy atomic.Bool
+		hits    atomic.Int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`"1.0.0"`))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	client := NewClient(WithBaseURL(server.URL), WithCircuitBreaker(2, time.Minute))
+	client.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Two failures trip the breaker
+	for i := 0; i < 2; i++ {
+		if _, err := client.Version(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Request %d: expected server error, got %v", i, err)
+		}
+	}
+	if _, err := client.Version(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("Expected open circuit not to reach the server, got %d hits", hits.Load())
+	}
+
+	// After the cooldown a failed probe reopens the circuit
+	now = now.Add(time.Minute)
+	if _, err := client.Version(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected probe to reach the server, got %v", err)
+	}
+	if _, err := client.Version(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected failed probe to reopen the circuit, got %v", err)
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	healthy.Store(true)
+	for i := 0; i < 2; i++ {
+		if _, err := client.Version(ctx); err != nil {
+			t.Fatalf("Request %d after recovery: %v", i, err)
+		}
+	}
+}
+
+func TestCircuitBreakerCountsClientTimeouts(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(WithBaseURL(server.URL), WithTimeout(20*time.Millisecond), WithCircuitBreaker(2, time.Minute))
+	for i := 0; i < 2; i++ {
+		if _, err := client.Version(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Request %d: expected client timeout, got %v", i, err)
+		}
+	}
+	if _, err := client.Version(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected client timeouts to trip the breaker, got %v", err)
+	}
+}
+
+func TestCircuitBreakerCountsCallerDeadlines(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	metrics := &recordingMetrics{}
+	client := NewClient(WithBaseURL(server.URL), WithCircuitBreaker(2, time.Minute), WithMetrics(metrics))
+
+	// Cancelling a request in flight is not the server's fault
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		if _, err := client.Version(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Request %d: expected cancellation, got %v", i, err)
+		}
+	}
+
+	// A caller deadline replaces the client timeout, so it counts the same
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := client.Version(ctx)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Request %d: expected caller deadline, got %v", i, err)
+		}
+	}
+	if _, err := client.Version(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected caller deadlines to trip the breaker, got %v", err)
+	}
+	if n := len(metrics.requests); n != 4 {
+		t.Errorf("Expected rejected requests not to be observed, got %d observations", n)
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithCircuitBreaker(1, time.Minute))
+	for i := 0; i < 3; i++ {
+		_, err := client.GetCollection(context.Background(), "missing", "", "")
+		if errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Request %d: expected 404s not to trip the breaker", i)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 3; i++ {
+		if _, err := client.Version(ctx); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Request %d: expected cancelled requests not to trip the breaker", i)
+		}
+	}
+}
+
+func TestWithCircuitBreakerInvalid(t *testing.T) {
+	client := NewClient(WithCircuitBreaker(0, time.Minute))
+	if client.Err() == nil {
+		t.Error("Expected error for zero threshold")
+	}
+}
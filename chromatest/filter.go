@@ -0,0 +1,148 @@
+package chromatest
+
+import (
+	"reflect"
+	"strings"
+)
+
+// matchWhere reports whether metadata satisfies a Where filter. It supports
+// plain equality, $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin, $and and $or.
+func matchWhere(metadata, where map[string]interface{}) bool {
+	for key, cond := range where {
+		switch key {
+		case "$and":
+			for _, sub := range subFilters(cond) {
+				if !matchWhere(metadata, sub) {
+					return false
+				}
+			}
+		case "$or":
+			matched := false
+			for _, sub := range subFilters(cond) {
+				if matchWhere(metadata, sub) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		default:
+			value, ok := metadata[key]
+			ops, isOps := cond.(map[string]interface{})
+			if !isOps {
+				ops = map[string]interface{}{"$eq": cond}
+			}
+			for op, operand := range ops {
+				if !matchOp(op, value, ok, operand) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// matchOp applies one comparison operator to a metadata value. present is
+// false when the key is missing, which only $ne and $nin accept.
+func matchOp(op string, value interface{}, present bool, operand interface{}) bool {
+	switch op {
+	case "$eq":
+		return present && equal(value, operand)
+	case "$ne":
+		return !present || !equal(value, operand)
+	case "$in", "$nin":
+		list, _ := operand.([]interface{})
+		found := false
+		for _, item := range list {
+			if present && equal(value, item) {
+				found = true
+				break
+			}
+		}
+		return found == (op == "$in")
+	case "$gt", "$gte", "$lt", "$lte":
+		a, okA := number(value)
+		b, okB := number(operand)
+		if !present || !okA || !okB {
+			return false
+		}
+		switch op {
+		case "$gt":
+			return a > b
+		case "$gte":
+			return a >= b
+		case "$lt":
+			return a < b
+		default:
+			return a <= b
+		}
+	}
+	return false
+}
+
+// matchDocument reports whether a document satisfies a WhereDocument filter.
+// It supports $contains, $not_contains, $and and $or.
+func matchDocument(document string, where map[string]interface{}) bool {
+	for key, cond := range where {
+		switch key {
+		case "$contains":
+			s, _ := cond.(string)
+			if !strings.Contains(document, s) {
+				return false
+			}
+		case "$not_contains":
+			s, _ := cond.(string)
+			if strings.Contains(document, s) {
+				return false
+			}
+		case "$and":
+			for _, sub := range subFilters(cond) {
+				if !matchDocument(document, sub) {
+					return false
+				}
+			}
+		case "$or":
+			matched := false
+			for _, sub := range subFilters(cond) {
+				if matchDocument(document, sub) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// subFilters returns the operands of $and or $or
+func subFilters(v interface{}) []map[string]interface{} {
+	list, _ := v.([]interface{})
+	subs := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			subs = append(subs, m)
+		}
+	}
+	return subs
+}
+
+// equal compares two decoded JSON values, treating all numbers alike
+func equal(a, b interface{}) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// number returns v as a float64 if it is a decoded JSON number
+func number(v interface{}) (float64, bool) {
+	n, ok := v.(float64)
+	return n, ok
+}
@@ -0,0 +1,597 @@
+// Package chromatest provides an in-memory ChromaDB server for testing code
+// built on chromaclient without a real Chroma instance.
+//
+// The mock speaks enough of the v2 API for typical application tests:
+// version, heartbeat, pre-flight checks, collection CRUD and the add,
+// upsert, update, get, delete, count and query record endpoints. Queries are
+// answered by brute-force nearest-neighbor search over the stored
+// embeddings, or with a canned result set with SetQueryResult. Errors can be
+// injected with FailNext and FailOn.
+package chromatest
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	chromaclient "github.com/kevensen/go-chroma-client"
+)
+
+// Version is the server version the mock reports
+const Version = "0.0.0-chromatest"
+
+// MaxBatchSize is the max_batch_size the mock reports in pre-flight checks
+const MaxBatchSize = 1000
+
+// Server is an in-memory ChromaDB server. Its embedded httptest.Server
+// provides the URL; close it with Close when the test is done.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	collections  map[string]*collection
+	nextID       int
+	queryResults map[string]chromaclient.QueryResult
+	next         []failure
+	rules        []failure
+}
+
+type collection struct {
+	info    chromaclient.Collection
+	ids     []string
+	records map[string]record
+}
+
+type record struct {
+	embedding []float64
+	document  string
+	metadata  map[string]interface{}
+	uri       string
+}
+
+type failure struct {
+	method, suffix string
+	status         int
+	message        string
+}
+
+// NewMockServer starts an empty mock server
+func NewMockServer() *Server {
+	s := &Server{
+		collections:  make(map[string]*collection),
+		queryResults: make(map[string]chromaclient.QueryResult),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// NewClient returns a client for the mock server. opts are applied after the
+// base URL.
+func (s *Server) NewClient(opts ...chromaclient.ClientOption) *chromaclient.Client {
+	return chromaclient.NewClient(append([]chromaclient.ClientOption{chromaclient.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// SetQueryResult makes queries against the collection with the given name or
+// ID return result instead of searching the stored records
+func (s *Server) SetQueryResult(collection string, result chromaclient.QueryResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queryResults[collection] = result
+}
+
+// FailNext makes the next request fail with the given status and message.
+// Calls queue up, one failure per request.
+func (s *Server) FailNext(status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next = append(s.next, failure{status: status, message: message})
+}
+
+// FailOn makes every request with the given method whose path ends with
+// suffix fail with the given status and message, e.g.
+// FailOn(http.MethodPost, "/query", 500, "boom"). An empty method matches any
+// method.
+func (s *Server) FailOn(method, suffix string, status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = append(s.rules, failure{method: method, suffix: suffix, status: status, message: message})
+}
+
+// ClearFailures removes the failures set up with FailNext and FailOn
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next, s.rules = nil, nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f, ok := s.failure(r); ok {
+		writeError(w, f.status, f.message)
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/api/v2")
+	if !ok {
+		writeError(w, http.StatusNotFound, "chromatest only serves the v2 API")
+		return
+	}
+	switch path {
+	case "/version":
+		writeJSON(w, http.StatusOK, Version)
+		return
+	case "/heartbeat":
+		writeJSON(w, http.StatusOK, chromaclient.HeartbeatResponse{NanosecondHeartbeat: time.Now().UnixNano()})
+		return
+	case "/pre-flight-checks":
+		writeJSON(w, http.StatusOK, chromaclient.PreflightChecks{"max_batch_size": MaxBatchSize})
+		return
+	case "/reset":
+		s.collections = make(map[string]*collection)
+		writeJSON(w, http.StatusOK, true)
+		return
+	}
+
+	// /tenants/{tenant}/databases/{database}/collections...
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 5 || parts[0] != "tenants" || parts[2] != "databases" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("chromatest does not serve %s", r.URL.Path))
+		return
+	}
+	tenant, database := parts[1], parts[3]
+
+	switch {
+	case len(parts) == 5 && parts[4] == "collections_count":
+		writeJSON(w, http.StatusOK, len(s.scoped(tenant, database)))
+	case len(parts) == 5 && parts[4] == "collections":
+		switch r.Method {
+		case http.MethodGet:
+			s.listCollections(w, r, tenant, database)
+		case http.MethodPost:
+			s.createCollection(w, r, tenant, database)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, r.Method+" not allowed")
+		}
+	case len(parts) == 6 && parts[4] == "collections":
+		s.serveCollection(w, r, tenant, database, parts[5])
+	case len(parts) == 7 && parts[4] == "collections":
+		col := s.lookup(tenant, database, parts[5])
+		if col == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("collection %s does not exist", parts[5]))
+			return
+		}
+		s.serveRecords(w, r, col, parts[6])
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("chromatest does not serve %s", r.URL.Path))
+	}
+}
+
+// failure returns the injected failure for r, if any
+func (s *Server) failure(r *http.Request) (failure, bool) {
+	if len(s.next) > 0 {
+		f := s.next[0]
+		s.next = s.next[1:]
+		return f, true
+	}
+	for _, f := range s.rules {
+		if (f.method == "" || f.method == r.Method) && strings.HasSuffix(r.URL.Path, f.suffix) {
+			return f, true
+		}
+	}
+	return failure{}, false
+}
+
+// scoped returns the collections of a tenant and database, oldest first
+func (s *Server) scoped(tenant, database string) []*collection {
+	var cols []*collection
+	for _, col := range s.collections {
+		if col.info.Tenant == tenant && col.info.Database == database {
+			cols = append(cols, col)
+		}
+	}
+	sort.Slice(cols, func(i, j int) bool { return cols[i].info.ID < cols[j].info.ID })
+	return cols
+}
+
+// lookup finds a collection by ID or by name within a tenant and database
+func (s *Server) lookup(tenant, database, idOrName string) *collection {
+	if col, ok := s.collections[idOrName]; ok && col.info.Tenant == tenant && col.info.Database == database {
+		return col
+	}
+	for _, col := range s.scoped(tenant, database) {
+		if col.info.Name == idOrName {
+			return col
+		}
+	}
+	return nil
+}
+
+func (s *Server) listCollections(w http.ResponseWriter, r *http.Request, tenant, database string) {
+	cols := s.scoped(tenant, database)
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	offset = min(max(offset, 0), len(cols))
+	cols = cols[offset:]
+	if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit >= 0 && limit < len(cols) {
+		cols = cols[:limit]
+	}
+
+	infos := make([]chromaclient.Collection, len(cols))
+	for i, col := range cols {
+		infos[i] = col.info
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (s *Server) createCollection(w http.ResponseWriter, r *http.Request, tenant, database string) {
+	var req chromaclient.CreateCollection
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "collection name is required")
+		return
+	}
+	if existing := s.lookup(tenant, database, req.Name); existing != nil {
+		if req.GetOrCreate {
+			writeJSON(w, http.StatusOK, existing.info)
+			return
+		}
+		writeError(w, http.StatusConflict, fmt.Sprintf("collection %s already exists", req.Name))
+		return
+	}
+
+	s.nextID++
+	info := chromaclient.Collection{
+		ID:       fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID),
+		Name:     req.Name,
+		Tenant:   tenant,
+		Database: database,
+		Metadata: req.Metadata,
+		Schema:   req.Schema,
+	}
+	if req.Configuration != nil {
+		info.ConfigurationJSON = *req.Configuration
+	}
+	s.collections[info.ID] = &collection{info: info, records: make(map[string]record)}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (s *Server) serveCollection(w http.ResponseWriter, r *http.Request, tenant, database, idOrName string) {
+	col := s.lookup(tenant, database, idOrName)
+	if col == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("collection %s does not exist", idOrName))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, col.info)
+	case http.MethodDelete:
+		delete(s.collections, col.info.ID)
+		writeJSON(w, http.StatusOK, struct{}{})
+	case http.MethodPut:
+		var req chromaclient.UpdateCollection
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.NewName != nil {
+			col.info.Name = *req.NewName
+		}
+		if req.NewMetadata != nil {
+			col.info.Metadata = req.NewMetadata
+		}
+		writeJSON(w, http.StatusOK, struct{}{})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, r.Method+" not allowed")
+	}
+}
+
+func (s *Server) serveRecords(w http.ResponseWriter, r *http.Request, col *collection, action string) {
+	switch action {
+	case "count":
+		writeJSON(w, http.StatusOK, len(col.ids))
+	case "add", "upsert", "update":
+		var req chromaclient.UpdateEmbedding
+		if !decode(w, r, &req) {
+			return
+		}
+		if err := col.write(action, req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		status := http.StatusOK
+		if action == "add" {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, struct{}{})
+	case "get":
+		var req chromaclient.GetEmbedding
+		if !decode(w, r, &req) {
+			return
+		}
+		writeJSON(w, http.StatusOK, col.get(req))
+	case "delete":
+		var req chromaclient.DeleteEmbedding
+		if !decode(w, r, &req) {
+			return
+		}
+		col.delete(req)
+		writeJSON(w, http.StatusOK, struct{}{})
+	case "query":
+		var req chromaclient.QueryEmbedding
+		if !decode(w, r, &req) {
+			return
+		}
+		if result, ok := s.queryResults[col.info.ID]; ok {
+			writeJSON(w, http.StatusOK, result)
+			return
+		}
+		if result, ok := s.queryResults[col.info.Name]; ok {
+			writeJSON(w, http.StatusOK, result)
+			return
+		}
+		if len(req.QueryEmbeddings) == 0 {
+			writeError(w, http.StatusBadRequest, "chromatest needs query_embeddings; use SetQueryResult to answer text queries")
+			return
+		}
+		writeJSON(w, http.StatusOK, col.query(req))
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("chromatest does not serve %s", r.URL.Path))
+	}
+}
+
+// write applies an add, upsert or update. Adding an existing ID leaves it
+// unchanged, and updating a missing ID is ignored, as on a real server.
+func (col *collection) write(action string, req chromaclient.UpdateEmbedding) error {
+	for _, column := range []struct {
+		name string
+		n    int
+	}{
+		{"embeddings", len(req.Embeddings)},
+		{"documents", len(req.Documents)},
+		{"metadatas", len(req.Metadatas)},
+		{"uris", len(req.Uris)},
+	} {
+		if column.n != 0 && column.n != len(req.IDs) {
+			return fmt.Errorf("%s has %d entries but ids has %d", column.name, column.n, len(req.IDs))
+		}
+	}
+
+	for i, id := range req.IDs {
+		rec, exists := col.records[id]
+		if (action == "add" && exists) || (action == "update" && !exists) {
+			continue
+		}
+		if action != "update" && len(req.Embeddings) == 0 && len(req.Documents) == 0 {
+			return fmt.Errorf("record %s has no embedding", id)
+		}
+
+		if len(req.Embeddings) > 0 {
+			rec.embedding = req.Embeddings[i]
+		}
+		if len(req.Documents) > 0 {
+			rec.document = req.Documents[i]
+		}
+		if len(req.Uris) > 0 {
+			rec.uri = req.Uris[i]
+		}
+		if len(req.Metadatas) > 0 {
+			rec.metadata = mergeMetadata(rec.metadata, req.Metadatas[i], action != "update")
+		}
+		if !exists {
+			col.ids = append(col.ids, id)
+		}
+		col.records[id] = rec
+		if d := len(rec.embedding); d > 0 && col.info.Dimension == nil {
+			dim := int32(d)
+			col.info.Dimension = &dim
+		}
+	}
+	return nil
+}
+
+// mergeMetadata applies patch to old. A nil value in patch deletes the key.
+// With replace set, keys missing from patch are dropped as well.
+func mergeMetadata(old, patch map[string]interface{}, replace bool) map[string]interface{} {
+	merged := make(map[string]interface{})
+	if !replace {
+		for k, v := range old {
+			merged[k] = v
+		}
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = v
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// matching returns the IDs, in insertion order, that satisfy the ID list and
+// filters of a request
+func (col *collection) matching(ids []string, where, whereDocument map[string]interface{}) []string {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var out []string
+	for _, id := range col.ids {
+		rec := col.records[id]
+		if len(ids) > 0 && !wanted[id] {
+			continue
+		}
+		if !matchWhere(rec.metadata, where) || !matchDocument(rec.document, whereDocument) {
+			continue
+		}
+		out = append(out, id)
+	}
+	return out
+}
+
+func (col *collection) get(req chromaclient.GetEmbedding) chromaclient.GetResult {
+	ids := col.matching(req.IDs, req.Where, req.WhereDocument)
+	if req.Offset != nil {
+		ids = ids[min(max(*req.Offset, 0), len(ids)):]
+	}
+	if req.Limit != nil && *req.Limit >= 0 && *req.Limit < len(ids) {
+		ids = ids[:*req.Limit]
+	}
+
+	include := includeSet(req.Include, chromaclient.IncludeDocuments, chromaclient.IncludeMetadatas)
+	result := chromaclient.GetResult{IDs: ids, Include: req.Include}
+	if result.IDs == nil {
+		result.IDs = []string{}
+	}
+	for _, id := range ids {
+		rec := col.records[id]
+		if include[chromaclient.IncludeEmbeddings] {
+			result.Embeddings = append(result.Embeddings, rec.embedding)
+		}
+		if include[chromaclient.IncludeDocuments] {
+			result.Documents = append(result.Documents, rec.document)
+		}
+		if include[chromaclient.IncludeMetadatas] {
+			result.Metadatas = append(result.Metadatas, rec.metadata)
+		}
+		if include[chromaclient.IncludeUris] {
+			result.Uris = append(result.Uris, rec.uri)
+		}
+	}
+	return result
+}
+
+func (col *collection) delete(req chromaclient.DeleteEmbedding) {
+	for _, id := range col.matching(req.IDs, req.Where, req.WhereDocument) {
+		delete(col.records, id)
+	}
+	kept := col.ids[:0]
+	for _, id := range col.ids {
+		if _, ok := col.records[id]; ok {
+			kept = append(kept, id)
+		}
+	}
+	col.ids = kept
+}
+
+func (col *collection) query(req chromaclient.QueryEmbedding) chromaclient.QueryResult {
+	n := req.NResults
+	if n <= 0 {
+		n = 10
+	}
+	space := col.info.Space()
+	candidates := col.matching(req.IDs, req.Where, req.WhereDocument)
+	include := includeSet(req.Include, chromaclient.IncludeDocuments, chromaclient.IncludeMetadatas, chromaclient.IncludeDistances)
+
+	result := chromaclient.QueryResult{Include: req.Include}
+	for _, q := range req.QueryEmbeddings {
+		type hit struct {
+			id       string
+			distance float64
+		}
+		var hits []hit
+		for _, id := range candidates {
+			if e := col.records[id].embedding; len(e) == len(q) {
+				hits = append(hits, hit{id, space.Distance(q, e)})
+			}
+		}
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].distance < hits[j].distance })
+		hits = hits[:min(n, len(hits))]
+
+		ids := []string{}
+		var (
+			embeddings [][]float64
+			documents  []string
+			metadatas  []map[string]interface{}
+			distances  []float64
+			uris       []string
+		)
+		for _, h := range hits {
+			rec := col.records[h.id]
+			ids = append(ids, h.id)
+			embeddings = append(embeddings, rec.embedding)
+			documents = append(documents, rec.document)
+			metadatas = append(metadatas, rec.metadata)
+			distances = append(distances, h.distance)
+			uris = append(uris, rec.uri)
+		}
+		result.IDs = append(result.IDs, ids)
+		if include[chromaclient.IncludeEmbeddings] {
+			result.Embeddings = append(result.Embeddings, embeddings)
+		}
+		if include[chromaclient.IncludeDocuments] {
+			result.Documents = append(result.Documents, documents)
+		}
+		if include[chromaclient.IncludeMetadatas] {
+			result.Metadatas = append(result.Metadatas, metadatas)
+		}
+		if include[chromaclient.IncludeDistances] {
+			result.Distances = append(result.Distances, distances)
+		}
+		if include[chromaclient.IncludeUris] {
+			result.Uris = append(result.Uris, uris)
+		}
+	}
+	return result
+}
+
+// includeSet returns the requested include fields, or defaults if none were
+// requested
+func includeSet(include []chromaclient.Include, defaults ...chromaclient.Include) map[chromaclient.Include]bool {
+	if len(include) == 0 {
+		include = defaults
+	}
+	set := make(map[chromaclient.Include]bool, len(include))
+	for _, inc := range include {
+		set[inc] = true
+	}
+	return set
+}
+
+// decode reads a JSON request body, gunzipping it if the client sent it with
+// Content-Encoding: gzip, and writes a 400 response on failure
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid gzip request body: %v", err))
+			return false
+		}
+		defer zr.Close()
+		body = zr
+	}
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"error":   http.StatusText(status),
+		"message": message,
+	})
+}
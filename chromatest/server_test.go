@@ -0,0 +1,182 @@
+package chromatest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	chromaclient "github.com/kevensen/go-chroma-client"
+	"github.com/kevensen/go-chroma-client/where"
+)
+
+func TestMockServerRecords(t *testing.T) {
+	srv := NewMockServer()
+	defer srv.Close()
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	version, err := client.Version(ctx)
+	if err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if version != Version {
+		t.Errorf("Expected version %s, got %s", Version, version)
+	}
+
+	col, err := client.CreateCollection(ctx, chromaclient.CreateCollection{Name: "docs"}, "", "")
+	if err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+	err = client.Add(ctx, col.ID, chromaclient.AddEmbedding{
+		IDs:        []string{"a", "b", "c"},
+		Embeddings: [][]float64{{0, 0}, {1, 0}, {5, 5}},
+		Documents:  []string{"alpha", "beta", "gamma"},
+		Metadatas: []map[string]interface{}{
+			{"kind": "letter", "rank": 1},
+			{"kind": "letter", "rank": 2},
+			{"kind": "other", "rank": 3},
+		},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if count, err := client.Count(ctx, col.ID, "", ""); err != nil || count != 3 {
+		t.Fatalf("Count() = %d, %v", count, err)
+	}
+
+	got, err := client.Get(ctx, col.ID, chromaclient.GetEmbedding{
+		Where: where.And(where.Eq("kind", "letter"), where.Gt("rank", 1)),
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(got.IDs) != 1 || got.IDs[0] != "b" || got.Documents[0] != "beta" {
+		t.Errorf("Expected only b, got %+v", got)
+	}
+
+	result, err := client.Query(ctx, col.ID, chromaclient.QueryEmbedding{
+		QueryEmbeddings: [][]float64{{0.9, 0}},
+		NResults:        2,
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	hits := result.Group(0)
+	if len(hits) != 2 || hits[0].ID != "b" || hits[1].ID != "a" {
+		t.Fatalf("Expected hits b, a, got %+v", hits)
+	}
+	if hits[0].Distance >= hits[1].Distance {
+		t.Errorf("Expected ascending distances, got %v and %v", hits[0].Distance, hits[1].Distance)
+	}
+
+	if err := client.Delete(ctx, col.ID, chromaclient.DeleteEmbedding{IDs: []string{"a"}}, "", ""); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if count, err := client.Count(ctx, col.ID, "", ""); err != nil || count != 2 {
+		t.Fatalf("Count() after delete = %d, %v", count, err)
+	}
+}
+
+func TestMockServerCollections(t *testing.T) {
+	srv := NewMockServer()
+	defer srv.Close()
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	if _, err := client.CreateCollection(ctx, chromaclient.CreateCollection{Name: "docs"}, "", ""); err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+	_, err := client.CreateCollection(ctx, chromaclient.CreateCollection{Name: "docs"}, "", "")
+	var httpErr *chromaclient.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 for duplicate collection, got %v", err)
+	}
+	if _, err := client.CreateCollectionWithSpace(ctx, "docs", chromaclient.SpaceCosine, "", ""); err != nil {
+		t.Errorf("Expected get_or_create to return the existing collection, got %v", err)
+	}
+
+	if exists, err := client.CollectionExists(ctx, "missing", "", ""); err != nil || exists {
+		t.Errorf("CollectionExists(missing) = %v, %v", exists, err)
+	}
+	if count, err := client.CountCollections(ctx, "", ""); err != nil || count != 1 {
+		t.Errorf("CountCollections() = %d, %v", count, err)
+	}
+	if err := client.DeleteCollection(ctx, "docs", "", ""); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+	if cols, err := client.ListCollections(ctx, "", ""); err != nil || len(cols) != 0 {
+		t.Errorf("ListCollections() = %v, %v", cols, err)
+	}
+}
+
+func TestMockServerCannedQueryAndFailures(t *testing.T) {
+	srv := NewMockServer()
+	defer srv.Close()
+	client := srv.NewClient()
+	ctx := context.Background()
+
+	col, err := client.CreateCollection(ctx, chromaclient.CreateCollection{Name: "docs"}, "", "")
+	if err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+	srv.SetQueryResult("docs", chromaclient.QueryResult{IDs: [][]string{{"canned"}}})
+	result, err := client.Query(ctx, col.ID, chromaclient.QueryEmbedding{QueryTexts: []string{"hello"}}, "", "")
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if result.IDs[0][0] != "canned" {
+		t.Errorf("Expected canned result, got %v", result.IDs)
+	}
+
+	srv.FailNext(http.StatusServiceUnavailable, "down for maintenance")
+	_, err = client.Heartbeat(ctx)
+	var httpErr *chromaclient.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected injected 503, got %v", err)
+	}
+	if _, err := client.Heartbeat(ctx); err != nil {
+		t.Errorf("Expected FailNext to fail only one request, got %v", err)
+	}
+
+	srv.FailOn(http.MethodPost, "/query", http.StatusInternalServerError, "boom")
+	if _, err := client.Query(ctx, col.ID, chromaclient.QueryEmbedding{QueryTexts: []string{"hello"}}, "", ""); err == nil {
+		t.Error("Expected injected query failure")
+	}
+	srv.ClearFailures()
+	if _, err := client.Query(ctx, col.ID, chromaclient.QueryEmbedding{QueryTexts: []string{"hello"}}, "", ""); err != nil {
+		t.Errorf("Expected query to succeed after ClearFailures, got %v", err)
+	}
+}
+
+func TestMockServerCompressedRequests(t *testing.T) {
+	srv := NewMockServer()
+	defer srv.Close()
+	client := srv.NewClient(chromaclient.WithCompression(1))
+	ctx := context.Background()
+
+	col, err := client.CreateCollectionWithSpace(ctx, "docs", chromaclient.SpaceCosine, "", "")
+	if err != nil {
+		t.Fatalf("CreateCollectionWithSpace() error = %v", err)
+	}
+	err = client.Add(ctx, col.ID, chromaclient.AddEmbedding{
+		IDs:        []string{"near", "far"},
+		Embeddings: [][]float64{{10, 1}, {0, 1}},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	// cosine distance ignores magnitude, so near wins despite its length
+	result, err := client.Query(ctx, col.ID, chromaclient.QueryEmbedding{
+		QueryEmbeddings: [][]float64{{1, 0}},
+		NResults:        1,
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(result.IDs[0]) != 1 || result.IDs[0][0] != "near" {
+		t.Errorf("Expected near under cosine distance, got %v", result.IDs)
+	}
+}
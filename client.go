@@ -4,10 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"mime"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -18,8 +25,53 @@ type Client struct {
 	httpClient *http.Client
 	tenant     string
 	database   string
+	authToken  string
+	timeout    time.Duration
+	apiVersion string
+
+	maxResponseBytes int64
+
+	logger        *slog.Logger
+	slowThreshold time.Duration
+	logBodies     bool
+	startSpan     spanStarter
+	inspect       func(*http.Response)
+	requestID     func() string
+	metrics       Metrics
+	breaker       *circuitBreaker
+
+	compressMinSize    int
+	useNumber          bool
+	defaultInclude     []Include
+	autoIdempotencyKey bool
+	validateWhere      bool
+	duplicateIDs       DuplicateIDPolicy
+
+	embeddingFunction  EmbeddingFunction
+	embeddingCache     *embeddingCache
+	skipDimensionCheck bool
+
+	// transport is the client's own transport, configured by the TLS and
+	// proxy options. It is unused when WithHTTPClient supplies a client
+	// with a different transport.
+	transport           *http.Transport
+	transportConfigured bool
+
+	// err records options that could not be applied. It is returned by Err
+	// and by every request so that NewClient can keep its signature.
+	err error
 }
 
+// API versions supported by WithAPIVersion
+const (
+	APIVersionV1 = "v1"
+	APIVersionV2 = "v2"
+)
+
+// DefaultMaxResponseBytes is the largest response body the client reads
+// unless changed with WithMaxResponseBytes
+const DefaultMaxResponseBytes = 64 << 20
+
 // ClientOption is a function that configures a Client
 type ClientOption func(*Client)
 
@@ -51,33 +103,192 @@ func WithDatabase(database string) ClientOption {
 	}
 }
 
+// WithAPIVersion selects the API path prefix, APIVersionV1 or APIVersionV2.
+// The default is APIVersionV2.
+func WithAPIVersion(version string) ClientOption {
+	return func(c *Client) {
+		if version != APIVersionV1 && version != APIVersionV2 {
+			c.addErr(fmt.Errorf("unsupported API version %q", version))
+			return
+		}
+		c.apiVersion = version
+	}
+}
+
+// WithDefaultInclude sets the columns Get and Query return when a request's
+// Include is empty. An Include set on a request replaces the default.
+// IncludeDistances applies to Query only and is left out of Get requests.
+func WithDefaultInclude(includes ...Include) ClientOption {
+	return func(c *Client) {
+		c.defaultInclude = includes
+	}
+}
+
+// defaultGetInclude returns the default Include for Get requests, which
+// cannot ask for distances
+func (c *Client) defaultGetInclude() []Include {
+	if len(c.defaultInclude) == 0 {
+		return nil
+	}
+	return slices.DeleteFunc(slices.Clone(c.defaultInclude), func(inc Include) bool {
+		return inc == IncludeDistances
+	})
+}
+
+// WithUseNumber decodes numbers in untyped response fields, such as metadata
+// values, as json.Number instead of float64, so integers above 2^53 keep
+// their precision. Read them with MetadataInt and MetadataFloat. It is off by
+// default.
+func WithUseNumber(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.useNumber = enabled
+	}
+}
+
+// WithAuthToken sets a token sent as a bearer Authorization header on every
+// request
+func WithAuthToken(token string) ClientOption {
+	return func(c *Client) {
+		c.authToken = token
+	}
+}
+
+// WithTimeout sets the overall timeout for each request. A deadline on the
+// context passed to a method takes precedence, so a single call can be given
+// more or less time than the client default.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithMaxResponseBytes caps the size of a response body, after any gzip
+// decoding, so that a misbehaving server cannot exhaust memory. A request
+// whose response is larger fails. n <= 0 removes the limit.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// WithResponseInspector calls fn with every HTTP response the client
+// receives, including error responses, e.g. to read rate-limit or request ID
+// headers. fn gets a copy of the response whose headers are cloned and whose
+// body has already been read into memory, so it need not close it.
+func WithResponseInspector(fn func(*http.Response)) ClientOption {
+	return func(c *Client) {
+		c.inspect = fn
+	}
+}
+
 // NewClient creates a new ChromaDB client
 func NewClient(opts ...ClientOption) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
 	c := &Client{
 		baseURL: "http://localhost:8000",
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: transport,
 		},
-		tenant:   DefaultTenant,
-		database: DefaultDatabase,
+		tenant:     DefaultTenant,
+		database:   DefaultDatabase,
+		apiVersion: APIVersionV2,
+		transport:  transport,
+
+		maxResponseBytes: DefaultMaxResponseBytes,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if c.transportConfigured && c.httpClient.Transport != c.transport {
+		c.addErr(fmt.Errorf("TLS, proxy and transport options cannot be combined with WithHTTPClient; configure the custom client's transport instead"))
+	}
+	if cfg := c.transport.TLSClientConfig; cfg != nil && cfg.InsecureSkipVerify && c.logger != nil {
+		c.logger.Warn("chroma client TLS certificate verification is disabled", "base_url", c.baseURL)
+	}
+
+	// The timeout is applied per request as a context deadline, so that a
+	// caller's own deadline can replace it rather than race it
+	if c.timeout == 0 {
+		c.timeout = c.httpClient.Timeout
+	}
+	if c.httpClient.Timeout != 0 {
+		// Copy so a client passed to WithHTTPClient is not modified
+		httpClient := *c.httpClient
+		httpClient.Timeout = 0
+		c.httpClient = &httpClient
+	}
+
 	return c
 }
 
+// Tenant returns the default tenant used when a method is given an empty
+// tenant
+func (c *Client) Tenant() string {
+	return c.tenant
+}
+
+// DatabaseName returns the default database used when a method is given an
+// empty database. It is not called Database because Client.Database returns
+// a DatabaseClient scope.
+func (c *Client) DatabaseName() string {
+	return c.database
+}
+
+// Err returns the error from any option that could not be applied, such as
+// an unreadable certificate file. Requests made with such a client return the
+// same error.
+func (c *Client) Err() error {
+	return c.err
+}
+
+// addErr records an option error
+func (c *Client) addErr(err error) {
+	c.err = errors.Join(c.err, err)
+}
+
 // doRequest performs an HTTP request
-func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
-	var bodyReader io.Reader
+func (c *Client) doRequest(ctx context.Context, op, method, path string, body interface{}, result interface{}) error {
+	return c.doRequestWithHeader(ctx, op, method, path, nil, body, result)
+}
+
+// doRequestWithHeader performs an HTTP request with extra headers, such as
+// the Idempotency-Key of a write
+func (c *Client) doRequestWithHeader(ctx context.Context, op, method, path string, header http.Header, body interface{}, result interface{}) (err error) {
+	if c.err != nil {
+		return c.err
+	}
+	// callerCtx tells a caller's cancellation apart from a timeout: the
+	// circuit breaker ignores the former and counts the latter as a failure,
+	// whether it is the client timeout or the caller's own deadline
+	callerCtx := ctx
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var (
+		bodyReader io.Reader
+		jsonData   []byte
+		compressed bool
+	)
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewReader(jsonData)
+		payload := jsonData
+		if c.compressMinSize > 0 && len(jsonData) >= c.compressMinSize {
+			payload, err = gzipBytes(jsonData)
+			if err != nil {
+				return fmt.Errorf("failed to compress request body: %w", err)
+			}
+			compressed = true
+		}
+		bodyReader = bytes.NewReader(payload)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
@@ -85,31 +296,105 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
+	req.Header.Set("Accept", "application/json")
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if c.requestID != nil {
+		req.Header.Set(RequestIDHeader, c.requestID())
+	}
+
+	// A request the open breaker rejects never reaches the server, so it is
+	// not traced, logged or observed as one
+	if c.breaker != nil {
+		if err := c.breaker.allow(); err != nil {
+			return err
+		}
+	}
+
+	status := 0
+	if c.startSpan != nil {
+		var endSpan func(status int, err error)
+		req, endSpan = c.startSpan(req, op)
+		defer func() { endSpan(status, err) }()
+	}
+
+	start := time.Now()
+	defer func() {
+		elapsed := time.Since(start)
+		c.logRequest(req, path, jsonData, status, elapsed, err)
+		if c.metrics != nil {
+			c.metrics.ObserveRequest(op, status, elapsed)
+		}
+	}()
 
 	resp, err := c.httpClient.Do(req)
+	if c.breaker != nil {
+		if errors.Is(callerCtx.Err(), context.Canceled) {
+			c.breaker.abort()
+		} else {
+			c.breaker.done(err != nil || resp.StatusCode >= 500)
+		}
+	}
 	if err != nil {
+		// Report cancellation and deadlines as the context error itself
+		// rather than the transport error that wraps it
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("request interrupted: %w", ctxErr)
+		}
 		return fmt.Errorf("failed to perform request: %w", err)
 	}
 	defer resp.Body.Close()
+	status = resp.StatusCode
 
-	respBody, err := io.ReadAll(resp.Body)
+	decoded, err := decodedBody(resp)
 	if err != nil {
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
+	defer decoded.Close()
+	var respReader io.Reader = decoded
+	if c.maxResponseBytes > 0 {
+		// Read one byte past the limit to tell a body of exactly the limit
+		// from a larger one
+		respReader = io.LimitReader(respReader, c.maxResponseBytes+1)
+	}
+	respBody, err := io.ReadAll(respReader)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if c.maxResponseBytes > 0 && int64(len(respBody)) > c.maxResponseBytes {
+		return fmt.Errorf("failed to read response body: exceeds %d bytes", c.maxResponseBytes)
+	}
+	if c.inspect != nil {
+		inspected := *resp
+		inspected.Header = resp.Header.Clone()
+		inspected.Body = io.NopCloser(bytes.NewReader(respBody))
+		c.inspect(&inspected)
+	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return &HTTPError{
 			StatusCode: resp.StatusCode,
 			Message:    string(respBody),
 			Timestamp:  time.Now(),
+			RequestID:  req.Header.Get(RequestIDHeader),
 		}
 	}
 
 	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
+		if err := checkJSON(resp.Header.Get("Content-Type"), respBody); err != nil {
+			return err
+		}
+		if err := c.unmarshal(respBody, result); err != nil {
 			return fmt.Errorf("failed to unmarshal response: %w", err)
 		}
 	}
@@ -117,55 +402,246 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	return nil
 }
 
+// maxBodySnippet bounds how much of an unexpected response body is quoted
+// in an error
+const maxBodySnippet = 200
+
+// checkJSON rejects a response body that is neither labelled nor shaped as
+// JSON, such as an HTML error page from a misconfigured proxy. Bodies that
+// are valid JSON are accepted whatever their Content-Type, since some
+// servers and gateways label JSON as text/plain or omit the header.
+func checkJSON(contentType string, body []byte) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || json.Valid(body) {
+		return nil
+	}
+	if mediaType == "" {
+		mediaType = "a response without Content-Type"
+	}
+	snippet := strings.TrimSpace(string(body))
+	if len(snippet) > maxBodySnippet {
+		snippet = snippet[:maxBodySnippet] + "..."
+	}
+	return fmt.Errorf("server returned %s, expected JSON: %q", mediaType, snippet)
+}
+
+// unmarshal decodes a response body, keeping numbers in untyped fields as
+// json.Number when WithUseNumber is set
+func (c *Client) unmarshal(data []byte, result interface{}) error {
+	if !c.useNumber {
+		return json.Unmarshal(data, result)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(result)
+}
+
+// apiPath formats an API path and prepends the configured version prefix
+func (c *Client) apiPath(format string, args ...interface{}) string {
+	return "/api/" + c.apiVersion + fmt.Sprintf(format, args...)
+}
+
+// DetectAPIVersion probes the server's heartbeat endpoint, preferring v2 and
+// falling back to v1 if the v2 endpoint returns 404, and switches the client
+// to the detected version. Call it before sharing the client between
+// goroutines.
+func (c *Client) DetectAPIVersion(ctx context.Context) (string, error) {
+	var lastErr error
+	for _, version := range []string{APIVersionV2, APIVersionV1} {
+		err := c.doRequest(ctx, "DetectAPIVersion", http.MethodGet, "/api/"+version+"/heartbeat", nil, nil)
+		if err == nil {
+			c.apiVersion = version
+			return version, nil
+		}
+
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+			return "", fmt.Errorf("failed to detect API version: %w", err)
+		}
+		lastErr = err
+	}
+	return "", fmt.Errorf("failed to detect API version: no supported version found: %w", lastErr)
+}
+
 // Version returns the ChromaDB version
 func (c *Client) Version(ctx context.Context) (string, error) {
 	var version string
-	err := c.doRequest(ctx, http.MethodGet, "/api/v2/version", nil, &version)
+	err := c.doRequest(ctx, "Version", http.MethodGet, c.apiPath("/version"), nil, &version)
 	return version, err
 }
 
 // Heartbeat checks if the ChromaDB server is alive
 func (c *Client) Heartbeat(ctx context.Context) (*HeartbeatResponse, error) {
 	var result HeartbeatResponse
-	err := c.doRequest(ctx, http.MethodGet, "/api/v2/heartbeat", nil, &result)
+	err := c.doRequest(ctx, "Heartbeat", http.MethodGet, c.apiPath("/heartbeat"), nil, &result)
 	return &result, err
 }
 
+// HeartbeatTime returns the server's heartbeat as a time.Time, e.g. to
+// estimate clock skew against time.Now(). The nanosecond value is decoded
+// directly into an int64, so it is exact.
+func (c *Client) HeartbeatTime(ctx context.Context) (time.Time, error) {
+	result, err := c.Heartbeat(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, result.NanosecondHeartbeat), nil
+}
+
+// Status calls Heartbeat and Version and combines their results, e.g. to
+// check reachability and gate on the server version at startup. If either
+// call fails the fields from the other are still set and the errors are
+// joined.
+func (c *Client) Status(ctx context.Context) (ServerStatus, error) {
+	var status ServerStatus
+	var errs []error
+	if heartbeat, err := c.Heartbeat(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to get heartbeat: %w", err))
+	} else {
+		status.Healthy = heartbeat.NanosecondHeartbeat != 0
+		status.NanosecondHeartbeat = heartbeat.NanosecondHeartbeat
+	}
+	if version, err := c.Version(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to get version: %w", err))
+	} else {
+		status.Version = version
+	}
+	return status, errors.Join(errs...)
+}
+
+// Ping reports whether the server answers its heartbeat endpoint with a 2xx
+// response and a valid heartbeat. An unreachable or unhealthy server is
+// reported as false with a nil error; an error is returned only when ctx is
+// done, so callers can tell cancellation apart from a down server.
+func (c *Client) Ping(ctx context.Context) (bool, error) {
+	result, err := c.Heartbeat(ctx)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return false, ctxErr
+	}
+	if err == nil && result.NanosecondHeartbeat == 0 {
+		err = fmt.Errorf("empty heartbeat response")
+	}
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Debug("chroma ping failed", "error", err)
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// WaitForReady polls the heartbeat endpoint every interval until the server
+// answers, e.g. while it is still starting up. It returns nil once the server
+// is healthy and the context error if ctx is done first.
+func (c *Client) WaitForReady(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %v", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ok, err := c.Ping(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // Reset resets the ChromaDB database (WARNING: This deletes all data)
 func (c *Client) Reset(ctx context.Context) (bool, error) {
 	var result bool
-	err := c.doRequest(ctx, http.MethodPost, "/api/v2/reset", nil, &result)
+	err := c.doRequest(ctx, "Reset", http.MethodPost, c.apiPath("/reset"), nil, &result)
 	return result, err
 }
 
 // PreFlightChecks returns preflight check results
 func (c *Client) PreFlightChecks(ctx context.Context) (PreflightChecks, error) {
 	var result PreflightChecks
-	err := c.doRequest(ctx, http.MethodGet, "/api/v2/pre-flight-checks", nil, &result)
+	err := c.doRequest(ctx, "PreFlightChecks", http.MethodGet, c.apiPath("/pre-flight-checks"), nil, &result)
 	return result, err
 }
 
+// MaxBatchSize returns the maximum number of records the server accepts in a
+// single add, upsert or update, as reported in its pre-flight checks
+func (c *Client) MaxBatchSize(ctx context.Context) (int, error) {
+	checks, err := c.PreFlightChecks(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read max batch size: %w", err)
+	}
+
+	v, ok := checks["max_batch_size"]
+	if !ok {
+		return 0, fmt.Errorf("server did not report max_batch_size in pre-flight checks")
+	}
+	size, ok := toFloat64(v)
+	if !ok || size != math.Trunc(size) || size < 1 || size > math.MaxInt32 {
+		return 0, fmt.Errorf("server reported an invalid max_batch_size: %v", v)
+	}
+	return int(size), nil
+}
+
+// Capabilities returns the server's pre-flight checks parsed into a
+// ServerCapabilities. Unlike MaxBatchSize it does not fail on missing or
+// malformed keys; they are left at their zero value.
+func (c *Client) Capabilities(ctx context.Context) (ServerCapabilities, error) {
+	checks, err := c.PreFlightChecks(ctx)
+	if err != nil {
+		return ServerCapabilities{}, err
+	}
+
+	caps := ServerCapabilities{Raw: checks}
+	if size, ok := toFloat64(checks["max_batch_size"]); ok && size == math.Trunc(size) && size > 0 && size <= math.MaxInt32 {
+		caps.MaxBatchSize = int(size)
+	}
+	caps.SupportsBase64Encoding, _ = checks["supports_base64_encoding"].(bool)
+	return caps, nil
+}
+
 // Root returns root endpoint information
-func (c *Client) Root(ctx context.Context) (map[string]float64, error) {
-	var result map[string]float64
-	err := c.doRequest(ctx, http.MethodGet, "/api/v2", nil, &result)
+func (c *Client) Root(ctx context.Context) (*RootInfo, error) {
+	var result RootInfo
+	err := c.doRequest(ctx, "Root", http.MethodGet, c.apiPath(""), nil, &result)
+	return &result, err
+}
+
+// RootRaw returns the root endpoint document as decoded JSON, including any
+// fields RootInfo does not know about
+func (c *Client) RootRaw(ctx context.Context) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := c.doRequest(ctx, "Root", http.MethodGet, c.apiPath(""), nil, &result)
 	return result, err
 }
 
 // CreateTenant creates a new tenant
 func (c *Client) CreateTenant(ctx context.Context, req CreateTenant) (*Tenant, error) {
 	var result Tenant
-	err := c.doRequest(ctx, http.MethodPost, "/api/v2/tenants", req, &result)
+	err := c.doRequest(ctx, "CreateTenant", http.MethodPost, c.apiPath("/tenants"), req, &result)
 	return &result, err
 }
 
 // GetTenant gets a tenant by name
 func (c *Client) GetTenant(ctx context.Context, name string) (*GetTenantResponse, error) {
 	var result GetTenantResponse
-	err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/v2/tenants/%s", name), nil, &result)
+	err := c.doRequest(ctx, "GetTenant", http.MethodGet, c.apiPath("/tenants/%s", url.PathEscape(name)), nil, &result)
 	return &result, err
 }
 
+// ListTenants lists all tenants. Servers that do not support listing
+// tenants respond with a 404 or 405, which is returned as an *HTTPError.
+func (c *Client) ListTenants(ctx context.Context) ([]Tenant, error) {
+	var result []Tenant
+	err := c.doRequest(ctx, "ListTenants", http.MethodGet, c.apiPath("/tenants"), nil, &result)
+	return result, err
+}
+
 // CreateDatabase creates a new database
 func (c *Client) CreateDatabase(ctx context.Context, req CreateDatabase, tenant ...string) (*Database, error) {
 	tenantName := c.tenant
@@ -173,9 +649,9 @@ func (c *Client) CreateDatabase(ctx context.Context, req CreateDatabase, tenant
 		tenantName = tenant[0]
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases", url.QueryEscape(tenantName))
+	path := c.apiPath("/tenants/%s/databases", url.PathEscape(tenantName))
 	var result Database
-	err := c.doRequest(ctx, http.MethodPost, path, req, &result)
+	err := c.doRequest(ctx, "CreateDatabase", http.MethodPost, path, req, &result)
 	return &result, err
 }
 
@@ -186,14 +662,76 @@ func (c *Client) GetDatabase(ctx context.Context, name string, tenant ...string)
 		tenantName = tenant[0]
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s", url.QueryEscape(tenantName), name)
+	path := c.apiPath("/tenants/%s/databases/%s", url.PathEscape(tenantName), url.PathEscape(name))
 	var result Database
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &result)
+	err := c.doRequest(ctx, "GetDatabase", http.MethodGet, path, nil, &result)
 	return &result, err
 }
 
+// DeleteDatabase deletes a database by name. If the database does not exist
+// the returned error is an *HTTPError with StatusCode 404.
+func (c *Client) DeleteDatabase(ctx context.Context, name string, tenant ...string) error {
+	tenantName := c.tenant
+	if len(tenant) > 0 {
+		tenantName = tenant[0]
+	}
+
+	path := c.apiPath("/tenants/%s/databases/%s", url.PathEscape(tenantName), url.PathEscape(name))
+	return c.doRequest(ctx, "DeleteDatabase", http.MethodDelete, path, nil, nil)
+}
+
+// ListDatabases lists all databases in a tenant
+func (c *Client) ListDatabases(ctx context.Context, tenant string) ([]Database, error) {
+	return c.listDatabases(ctx, "ListDatabases", tenant, 0, 0)
+}
+
+// ListDatabasesPaged lists databases in a tenant using limit and offset.
+// A zero limit or offset is not sent, leaving the server default in place.
+func (c *Client) ListDatabasesPaged(ctx context.Context, tenant string, limit, offset int) ([]Database, error) {
+	return c.listDatabases(ctx, "ListDatabasesPaged", tenant, limit, offset)
+}
+
+// listDatabases lists databases in a tenant, reporting the request as op
+func (c *Client) listDatabases(ctx context.Context, op, tenant string, limit, offset int) ([]Database, error) {
+	if tenant == "" {
+		tenant = c.tenant
+	}
+
+	path := c.apiPath("/tenants/%s/databases", url.PathEscape(tenant)) + paginationQuery(limit, offset)
+	var result []Database
+	err := c.doRequest(ctx, op, http.MethodGet, path, nil, &result)
+	return result, err
+}
+
+// paginationQuery builds a limit/offset query string, omitting zero values
+func paginationQuery(limit, offset int) string {
+	values := url.Values{}
+	if limit > 0 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		values.Set("offset", strconv.Itoa(offset))
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
+
 // ListCollections lists all collections
 func (c *Client) ListCollections(ctx context.Context, tenant, database string) ([]Collection, error) {
+	return c.listCollections(ctx, "ListCollections", tenant, database, 0, 0)
+}
+
+// ListCollectionsPaged lists collections using limit and offset. A zero limit
+// or offset is not sent, leaving the server default in place. Combine it with
+// CountCollections to page through large databases.
+func (c *Client) ListCollectionsPaged(ctx context.Context, tenant, database string, limit, offset int) ([]Collection, error) {
+	return c.listCollections(ctx, "ListCollectionsPaged", tenant, database, limit, offset)
+}
+
+// listCollections lists collections, reporting the request as op
+func (c *Client) listCollections(ctx context.Context, op, tenant, database string, limit, offset int) ([]Collection, error) {
 	if tenant == "" {
 		tenant = c.tenant
 	}
@@ -201,11 +739,11 @@ func (c *Client) ListCollections(ctx context.Context, tenant, database string) (
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections",
-		url.QueryEscape(tenant), url.QueryEscape(database))
+	path := c.apiPath("/tenants/%s/databases/%s/collections",
+		url.PathEscape(tenant), url.PathEscape(database)) + paginationQuery(limit, offset)
 
 	var result []Collection
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &result)
+	err := c.doRequest(ctx, op, http.MethodGet, path, nil, &result)
 	return result, err
 }
 
@@ -218,16 +756,19 @@ func (c *Client) CountCollections(ctx context.Context, tenant, database string)
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections_count",
-		url.QueryEscape(tenant), url.QueryEscape(database))
+	path := c.apiPath("/tenants/%s/databases/%s/collections_count",
+		url.PathEscape(tenant), url.PathEscape(database))
 
 	var result int
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &result)
+	err := c.doRequest(ctx, "CountCollections", http.MethodGet, path, nil, &result)
 	return result, err
 }
 
 // CreateCollection creates a new collection
 func (c *Client) CreateCollection(ctx context.Context, req CreateCollection, tenant, database string) (*Collection, error) {
+	if err := validateConfiguration(req.Configuration); err != nil {
+		return nil, err
+	}
 	if tenant == "" {
 		tenant = c.tenant
 	}
@@ -235,15 +776,56 @@ func (c *Client) CreateCollection(ctx context.Context, req CreateCollection, ten
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections",
-		url.QueryEscape(tenant), url.QueryEscape(database))
+	path := c.apiPath("/tenants/%s/databases/%s/collections",
+		url.PathEscape(tenant), url.PathEscape(database))
 
 	var result Collection
-	err := c.doRequest(ctx, http.MethodPost, path, req, &result)
+	err := c.doRequest(ctx, "CreateCollection", http.MethodPost, path, req, &result)
 	return &result, err
 }
 
-// GetCollection gets a collection by name
+// GetOrCreateCollection returns the named collection, creating it with
+// metadata if it does not exist. The metadata of an existing collection is
+// left unchanged.
+func (c *Client) GetOrCreateCollection(ctx context.Context, name string, metadata map[string]interface{}, tenant, database string) (*Collection, error) {
+	return c.CreateCollection(ctx, CreateCollection{
+		Name:        name,
+		Metadata:    metadata,
+		GetOrCreate: true,
+	}, tenant, database)
+}
+
+// CreateCollectionWithSpace gets or creates a collection whose HNSW index uses
+// the given distance space
+func (c *Client) CreateCollectionWithSpace(ctx context.Context, name string, space Space, tenant, database string) (*Collection, error) {
+	if !space.Valid() {
+		return nil, fmt.Errorf("invalid space %q: must be one of l2, cosine or ip", space)
+	}
+
+	return c.CreateCollection(ctx, CreateCollection{
+		Name:        name,
+		GetOrCreate: true,
+		Configuration: &CollectionConfiguration{
+			Hnsw: &HnswConfiguration{Space: &space},
+		},
+	}, tenant, database)
+}
+
+// CreateCollectionWithSpann gets or creates a collection indexed with SPANN,
+// as used by distributed Chroma, using the given index settings
+func (c *Client) CreateCollectionWithSpann(ctx context.Context, name string, cfg SpannConfiguration, tenant, database string) (*Collection, error) {
+	return c.CreateCollection(ctx, CreateCollection{
+		Name:        name,
+		GetOrCreate: true,
+		Configuration: &CollectionConfiguration{
+			Spann: &cfg,
+		},
+	}, tenant, database)
+}
+
+// GetCollection gets a collection by name. The server always returns the
+// collection's configuration and schema, so ConfigurationJSON and Schema are
+// populated without further options.
 func (c *Client) GetCollection(ctx context.Context, name string, tenant, database string) (*Collection, error) {
 	if tenant == "" {
 		tenant = c.tenant
@@ -252,16 +834,19 @@ func (c *Client) GetCollection(ctx context.Context, name string, tenant, databas
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections/%s",
-		url.QueryEscape(tenant), url.QueryEscape(database), name)
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(name))
 
 	var result Collection
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &result)
+	err := c.doRequest(ctx, "GetCollection", http.MethodGet, path, nil, &result)
 	return &result, err
 }
 
-// DeleteCollection deletes a collection by name
-func (c *Client) DeleteCollection(ctx context.Context, name string, tenant, database string) error {
+// GetCollectionByID gets a collection by ID. The server resolves the
+// collection path segment as an ID or a name; the unscoped
+// /api/v2/collections/{crn} endpoint takes a resource name rather than an ID,
+// so the tenant and database are still needed.
+func (c *Client) GetCollectionByID(ctx context.Context, id string, tenant, database string) (*Collection, error) {
 	if tenant == "" {
 		tenant = c.tenant
 	}
@@ -269,14 +854,45 @@ func (c *Client) DeleteCollection(ctx context.Context, name string, tenant, data
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections/%s",
-		url.QueryEscape(tenant), url.QueryEscape(database), name)
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(id))
 
-	return c.doRequest(ctx, http.MethodDelete, path, nil, nil)
+	var result Collection
+	err := c.doRequest(ctx, "GetCollectionByID", http.MethodGet, path, nil, &result)
+	return &result, err
 }
 
-// UpdateCollection updates a collection
-func (c *Client) UpdateCollection(ctx context.Context, collectionID string, req UpdateCollection, tenant, database string) error {
+// ResolveCollection gets a collection by ID and checks that it is still named
+// expectedName, e.g. to catch a cached ID whose collection was renamed
+func (c *Client) ResolveCollection(ctx context.Context, id, expectedName string, tenant, database string) (*Collection, error) {
+	col, err := c.GetCollectionByID(ctx, id, tenant, database)
+	if err != nil {
+		return nil, err
+	}
+	if col.Name != expectedName {
+		return nil, fmt.Errorf("collection %s is named %q, expected %q", id, col.Name, expectedName)
+	}
+	return col, nil
+}
+
+// CollectionExists reports whether the named collection exists. A 404 from
+// the server is reported as false; any other failure is returned as an
+// error.
+func (c *Client) CollectionExists(ctx context.Context, name string, tenant, database string) (bool, error) {
+	_, err := c.GetCollection(ctx, name, tenant, database)
+	if err == nil {
+		return true, nil
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return false, err
+}
+
+// DeleteCollection deletes a collection by name
+func (c *Client) DeleteCollection(ctx context.Context, name string, tenant, database string) error {
 	if tenant == "" {
 		tenant = c.tenant
 	}
@@ -284,13 +900,17 @@ func (c *Client) UpdateCollection(ctx context.Context, collectionID string, req
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections/%s",
-		url.QueryEscape(tenant), url.QueryEscape(database), collectionID)
-	return c.doRequest(ctx, http.MethodPut, path, req, nil)
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(name))
+
+	return c.doRequest(ctx, "DeleteCollection", http.MethodDelete, path, nil, nil)
 }
 
-// Add adds embeddings to a collection
-func (c *Client) Add(ctx context.Context, collectionID string, req AddEmbedding, tenant, database string) error {
+// UpdateCollection updates a collection
+func (c *Client) UpdateCollection(ctx context.Context, collectionID string, req UpdateCollection, tenant, database string) error {
+	if err := validateConfiguration(req.NewConfiguration); err != nil {
+		return err
+	}
 	if tenant == "" {
 		tenant = c.tenant
 	}
@@ -298,9 +918,77 @@ func (c *Client) Add(ctx context.Context, collectionID string, req AddEmbedding,
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections/%s/add",
-		url.QueryEscape(tenant), url.QueryEscape(database), collectionID)
-	return c.doRequest(ctx, http.MethodPost, path, req, nil)
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(collectionID))
+	return c.doRequest(ctx, "UpdateCollection", http.MethodPut, path, req, nil)
+}
+
+// RenameCollection renames a collection and returns it with its new name
+func (c *Client) RenameCollection(ctx context.Context, collectionID, newName string, tenant, database string) (*Collection, error) {
+	if newName == "" {
+		return nil, fmt.Errorf("new collection name must not be empty")
+	}
+
+	err := c.UpdateCollection(ctx, collectionID, UpdateCollection{NewName: &newName}, tenant, database)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCollectionByID(ctx, collectionID, tenant, database)
+}
+
+// UpdateHNSW changes HNSW settings of an existing collection, such as
+// EfSearch, and returns the updated collection. Only non-nil fields are sent,
+// so other settings stay untouched. EfConstruction and Space are fixed when
+// the collection is created and are rejected.
+func (c *Client) UpdateHNSW(ctx context.Context, collectionID string, cfg HnswConfiguration, tenant, database string) (*Collection, error) {
+	if cfg.EfConstruction != nil || cfg.Space != nil {
+		return nil, fmt.Errorf("invalid configuration: ef_construction and space cannot be changed after a collection is created")
+	}
+
+	err := c.UpdateCollection(ctx, collectionID, UpdateCollection{
+		NewConfiguration: &CollectionConfiguration{Hnsw: &cfg},
+	}, tenant, database)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCollectionByID(ctx, collectionID, tenant, database)
+}
+
+// SetEmbeddingFunction changes the embedding function recorded in a
+// collection's configuration and returns the updated collection. Only the
+// embedding_function block is sent. The server may refuse the change, for
+// example when the collection's existing embedding function cannot be
+// replaced; the *HTTPError is returned as is, and the collection has to be
+// recreated with the new function instead (see CopyCollection).
+func (c *Client) SetEmbeddingFunction(ctx context.Context, collectionID string, cfg EmbeddingFunctionConfiguration, tenant, database string) (*Collection, error) {
+	if cfg.Type == "" {
+		return nil, fmt.Errorf("invalid configuration: embedding function type is required")
+	}
+	if cfg.Type == "known" && cfg.Name == "" {
+		return nil, fmt.Errorf("invalid configuration: a known embedding function needs a name")
+	}
+
+	err := c.UpdateCollection(ctx, collectionID, UpdateCollection{
+		NewConfiguration: &CollectionConfiguration{EmbeddingFunction: &cfg},
+	}, tenant, database)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCollectionByID(ctx, collectionID, tenant, database)
+}
+
+// Add adds embeddings to a collection. Each row needs an embedding or a
+// document. Documents without embeddings are embedded with the client's
+// embedding function when one is set, and otherwise by the server using the
+// collection's embedding function. The add is all or nothing: a nil error
+// means every row in req was stored, so len(req.IDs) is the added count. The
+// server's response body is an empty object and carries no count.
+func (c *Client) Add(ctx context.Context, collectionID string, req AddEmbedding, tenant, database string) error {
+	req, err := c.embedDocuments(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeRecords(c, ctx, "Add", "add", collectionID, req, tenant, database)
 }
 
 // Update updates embeddings in a collection
@@ -312,13 +1000,93 @@ func (c *Client) Update(ctx context.Context, collectionID string, req UpdateEmbe
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections/%s/update",
-		url.QueryEscape(tenant), url.QueryEscape(database), collectionID)
-	return c.doRequest(ctx, http.MethodPost, path, req, nil)
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s/update",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(collectionID))
+	return c.doRequest(ctx, "Update", http.MethodPost, path, req, nil)
+}
+
+// UpdateMetadataMerge applies patches[i] on top of the current metadata of
+// ids[i] and writes the result back with Update, leaving keys a patch does not
+// mention unchanged. A key set to nil in a patch is deleted; it is sent as
+// null, which the server treats as a delete. Every ID must exist.
+func (c *Client) UpdateMetadataMerge(ctx context.Context, collectionID string, ids []string, patches []map[string]interface{}, tenant, database string) error {
+	if len(patches) != len(ids) {
+		return fmt.Errorf("invalid records: patches has %d entries but IDs has %d", len(patches), len(ids))
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	current, err := c.GetByIDs(ctx, collectionID, ids, tenant, database, IncludeMetadatas)
+	if err != nil {
+		return fmt.Errorf("failed to get current metadata: %w", err)
+	}
+	existing := make(map[string]map[string]interface{}, len(current.IDs))
+	for i, id := range current.IDs {
+		existing[id] = at(current.Metadatas, i)
+	}
+
+	var missing []string
+	merged := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		old, ok := existing[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		m := make(map[string]interface{}, len(old)+len(patches[i]))
+		for k, v := range old {
+			m[k] = v
+		}
+		for k, v := range patches[i] {
+			m[k] = v
+		}
+		merged[i] = m
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("invalid records: ids not found: %s", strings.Join(missing, ", "))
+	}
+
+	return c.Update(ctx, collectionID, UpdateEmbedding{IDs: ids, Metadatas: merged}, tenant, database)
 }
 
-// Upsert upserts embeddings in a collection
+// Upsert upserts embeddings in a collection. It applies the same validation
+// as Add and, like Add, either stores every row or returns an error.
 func (c *Client) Upsert(ctx context.Context, collectionID string, req AddEmbedding, tenant, database string) error {
+	req, err := c.embedDocuments(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeRecords(c, ctx, "Upsert", "upsert", collectionID, req, tenant, database)
+}
+
+// records is an add or upsert payload, AddEmbedding or AddEmbedding32
+type records[R any] interface {
+	validate() error
+	recordIDs() []string
+	dedupKeepLast() R
+}
+
+// writeRecords validates and sends an add or upsert. It is the one request
+// path for float64 and float32 payloads, so the duplicate ID policy and
+// idempotency keys apply to both; only adds are checked for duplicates and
+// given automatic idempotency keys.
+func writeRecords[R records[R]](c *Client, ctx context.Context, op, action, collectionID string, req R, tenant, database string) error {
+	if err := req.validate(); err != nil {
+		return err
+	}
+	isAdd := action == "add"
+	if isAdd {
+		var err error
+		if req, err = resolveDuplicateIDs(c, req); err != nil {
+			return err
+		}
+	}
+	header, err := c.idempotencyHeader(ctx, req, isAdd)
+	if err != nil {
+		return err
+	}
+
 	if tenant == "" {
 		tenant = c.tenant
 	}
@@ -326,13 +1094,42 @@ func (c *Client) Upsert(ctx context.Context, collectionID string, req AddEmbeddi
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections/%s/upsert",
-		url.QueryEscape(tenant), url.QueryEscape(database), collectionID)
-	return c.doRequest(ctx, http.MethodPost, path, req, nil)
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s/%s",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(collectionID), action)
+	return c.doRequestWithHeader(ctx, op, http.MethodPost, path, header, req, nil)
+}
+
+// UpsertDocuments upserts a map of ID to document text, sending the records
+// sorted by ID. The documents are embedded with ef if it is non-nil, and
+// otherwise like Upsert: with the client's embedding function, or by the
+// server.
+func (c *Client) UpsertDocuments(ctx context.Context, collectionID string, docs map[string]string, ef EmbeddingFunction, tenant, database string) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	req := AddEmbedding{IDs: slices.Sorted(maps.Keys(docs))}
+	req.Documents = make([]string, len(req.IDs))
+	for i, id := range req.IDs {
+		req.Documents[i] = docs[id]
+	}
+	if ef != nil {
+		embeddings, err := embed(ctx, ef, req.Documents)
+		if err != nil {
+			return err
+		}
+		req.Embeddings = embeddings
+	}
+	return c.Upsert(ctx, collectionID, req, tenant, database)
 }
 
 // Get gets embeddings from a collection
 func (c *Client) Get(ctx context.Context, collectionID string, req GetEmbedding, tenant, database string) (*GetResult, error) {
+	if err := c.checkWhere(req.Where); err != nil {
+		return nil, err
+	}
+	if len(req.Include) == 0 {
+		req.Include = c.defaultGetInclude()
+	}
 	if tenant == "" {
 		tenant = c.tenant
 	}
@@ -340,15 +1137,39 @@ func (c *Client) Get(ctx context.Context, collectionID string, req GetEmbedding,
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections/%s/get",
-		url.QueryEscape(tenant), url.QueryEscape(database), collectionID)
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s/get",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(collectionID))
 	var result GetResult
-	err := c.doRequest(ctx, http.MethodPost, path, req, &result)
+	err := c.doRequest(ctx, "Get", http.MethodPost, path, req, &result)
 	return &result, err
 }
 
+// GetByIDs gets the records with the given IDs. include defaults to
+// documents and metadatas when empty.
+func (c *Client) GetByIDs(ctx context.Context, collectionID string, ids []string, tenant, database string, include ...Include) (*GetResult, error) {
+	if len(include) == 0 {
+		include = Includes(IncludeDocuments, IncludeMetadatas)
+	}
+	return c.Get(ctx, collectionID, GetEmbedding{IDs: ids, Include: include}, tenant, database)
+}
+
+// Peek returns the first n records of a collection with their documents and
+// metadatas, for a quick look at its contents. n defaults to 10 when n <= 0.
+func (c *Client) Peek(ctx context.Context, collectionID string, n int, tenant, database string) (*GetResult, error) {
+	if n <= 0 {
+		n = 10
+	}
+	return c.Get(ctx, collectionID, GetEmbedding{
+		Limit:   &n,
+		Include: Includes(IncludeDocuments, IncludeMetadatas),
+	}, tenant, database)
+}
+
 // Delete deletes embeddings from a collection
 func (c *Client) Delete(ctx context.Context, collectionID string, req DeleteEmbedding, tenant, database string) error {
+	if err := c.checkWhere(req.Where); err != nil {
+		return err
+	}
 	if tenant == "" {
 		tenant = c.tenant
 	}
@@ -356,9 +1177,24 @@ func (c *Client) Delete(ctx context.Context, collectionID string, req DeleteEmbe
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections/%s/delete",
-		url.QueryEscape(tenant), url.QueryEscape(database), collectionID)
-	return c.doRequest(ctx, http.MethodPost, path, req, nil)
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s/delete",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(collectionID))
+	return c.doRequest(ctx, "Delete", http.MethodPost, path, req, nil)
+}
+
+// DeleteDryRun reports how many records Delete would remove for req, without
+// deleting anything. It pages through the IDs matching req's IDs, Where and
+// WhereDocument filters without fetching any other columns, so large dry
+// runs stay cheap.
+func (c *Client) DeleteDryRun(ctx context.Context, collectionID string, req DeleteEmbedding, tenant, database string) (int, error) {
+	if err := c.checkWhere(req.Where); err != nil {
+		return 0, err
+	}
+	return c.countMatching(ctx, "DeleteDryRun", collectionID, idsOnlyGet{
+		IDs:           req.IDs,
+		Where:         req.Where,
+		WhereDocument: req.WhereDocument,
+	}, tenant, database)
 }
 
 // Count returns the number of embeddings in a collection
@@ -370,15 +1206,31 @@ func (c *Client) Count(ctx context.Context, collectionID string, tenant, databas
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections/%s/count",
-		url.QueryEscape(tenant), url.QueryEscape(database), collectionID)
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s/count",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(collectionID))
 	var result int
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &result)
+	err := c.doRequest(ctx, "Count", http.MethodGet, path, nil, &result)
 	return result, err
 }
 
-// Query queries a collection for nearest neighbors
+// Query queries a collection for nearest neighbors. Exactly one of
+// QueryEmbeddings or QueryTexts must be set. Query texts are embedded with the
+// client's embedding function when one is set.
 func (c *Client) Query(ctx context.Context, collectionID string, req QueryEmbedding, tenant, database string) (*QueryResult, error) {
+	if err := validateQuery(req); err != nil {
+		return nil, err
+	}
+	if err := c.checkWhere(req.Where); err != nil {
+		return nil, err
+	}
+	if len(req.Include) == 0 {
+		req.Include = c.defaultInclude
+	}
+	req, err := c.embedQueryTexts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
 	if tenant == "" {
 		tenant = c.tenant
 	}
@@ -386,9 +1238,39 @@ func (c *Client) Query(ctx context.Context, collectionID string, req QueryEmbedd
 		database = c.database
 	}
 
-	path := fmt.Sprintf("/api/v2/tenants/%s/databases/%s/collections/%s/query",
-		url.QueryEscape(tenant), url.QueryEscape(database), collectionID)
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s/query",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(collectionID))
 	var result QueryResult
-	err := c.doRequest(ctx, http.MethodPost, path, req, &result)
+	err = c.doRequest(ctx, "Query", http.MethodPost, path, req, &result)
 	return &result, err
 }
+
+// QueryStream runs Query and calls fn with each hit, in order, along with the
+// index of the query embedding or text it matched. Iteration stops at the
+// first error from fn, which is returned as is. The server's response is
+// column-major, so no hit is complete until the whole body is decoded; the
+// result is therefore decoded in one go and only the per-hit QueryHit values
+// are built lazily, one group at a time.
+func (c *Client) QueryStream(ctx context.Context, collectionID string, req QueryEmbedding, fn func(query int, hit QueryHit) error, tenant, database string) error {
+	result, err := c.Query(ctx, collectionID, req, tenant, database)
+	if err != nil {
+		return err
+	}
+	for i := range result.IDs {
+		for _, hit := range result.Group(i) {
+			if err := fn(i, hit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// QueryByText queries a collection using raw query texts. Without a client
+// embedding function the server embeds the texts, so the collection must have
+// been created with an embedding_function. Other query parameters such as
+// NResults, Where and Include are taken from req.
+func (c *Client) QueryByText(ctx context.Context, collectionID string, texts []string, req QueryEmbedding, tenant, database string) (*QueryResult, error) {
+	req.QueryTexts = texts
+	return c.Query(ctx, collectionID, req, tenant, database)
+}
@@ -1,11 +1,21 @@
 package chromaclient
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -42,6 +52,18 @@ func TestNewClientWithOptions(t *testing.T) {
 	}
 }
 
+func TestTenantAndDatabaseName(t *testing.T) {
+	client := NewClient()
+	if client.Tenant() != DefaultTenant || client.DatabaseName() != DefaultDatabase {
+		t.Errorf("Expected defaults, got %s/%s", client.Tenant(), client.DatabaseName())
+	}
+
+	client = NewClient(WithTenant("acme"), WithDatabase("prod"))
+	if client.Tenant() != "acme" || client.DatabaseName() != "prod" {
+		t.Errorf("Expected acme/prod, got %s/%s", client.Tenant(), client.DatabaseName())
+	}
+}
+
 func TestVersion(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/version" {
@@ -65,6 +87,67 @@ func TestVersion(t *testing.T) {
 	}
 }
 
+func TestAcceptHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept"); got != "application/json" {
+			t.Errorf("Expected Accept application/json, got %q", got)
+		}
+		if got := r.Header.Get("Content-Type"); got != "" {
+			t.Errorf("Expected no Content-Type on a GET without body, got %q", got)
+		}
+		w.Write([]byte(`"1.0.0"`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if _, err := client.Version(context.Background()); err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+}
+
+func TestNonJSONResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><body>502 Bad Gateway</body></html>"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	_, err := client.Version(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "server returned text/html, expected JSON") {
+		t.Fatalf("Expected non-JSON error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "502 Bad Gateway") {
+		t.Errorf("Expected error to quote the body, got %v", err)
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`"` + strings.Repeat("x", 98) + `"`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithMaxResponseBytes(99))
+	if _, err := client.Version(context.Background()); err == nil || !strings.Contains(err.Error(), "exceeds 99 bytes") {
+		t.Errorf("Expected size limit error, got %v", err)
+	}
+
+	client = NewClient(WithBaseURL(server.URL), WithMaxResponseBytes(100))
+	if _, err := client.Version(context.Background()); err != nil {
+		t.Errorf("Expected a body of exactly the limit to be read, got %v", err)
+	}
+
+	client = NewClient(WithBaseURL(server.URL), WithMaxResponseBytes(0))
+	if _, err := client.Version(context.Background()); err != nil {
+		t.Errorf("Expected no limit, got %v", err)
+	}
+	if client := NewClient(); client.maxResponseBytes != DefaultMaxResponseBytes {
+		t.Errorf("Expected default limit %d, got %d", DefaultMaxResponseBytes, client.maxResponseBytes)
+	}
+}
+
 func TestHeartbeat(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/heartbeat" {
@@ -88,6 +171,188 @@ func TestHeartbeat(t *testing.T) {
 	}
 }
 
+func TestCapabilities(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		body   string
+		size   int
+		base64 bool
+	}{
+		{"full", `{"max_batch_size": 5461, "supports_base64_encoding": true, "extra": "x"}`, 5461, true},
+		{"missing keys", `{}`, 0, false},
+		{"malformed", `{"max_batch_size": "lots", "supports_base64_encoding": "yes"}`, 0, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v2/pre-flight-checks" {
+					t.Errorf("Expected pre-flight path, got %s", r.URL.Path)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			caps, err := NewClient(WithBaseURL(server.URL)).Capabilities(context.Background())
+			if err != nil {
+				t.Fatalf("Capabilities() error = %v", err)
+			}
+			if caps.MaxBatchSize != tt.size || caps.SupportsBase64Encoding != tt.base64 {
+				t.Errorf("Expected %d/%v, got %d/%v", tt.size, tt.base64, caps.MaxBatchSize, caps.SupportsBase64Encoding)
+			}
+			if tt.name == "full" && caps.Raw["extra"] != "x" {
+				t.Errorf("Expected unparsed keys in Raw, got %v", caps.Raw)
+			}
+		})
+	}
+}
+
+func TestRoot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2" {
+			t.Errorf("Expected path /api/v2, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"nanosecond heartbeat": 1760000000123456789, "server": "chroma"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	info, err := client.Root(context.Background())
+	if err != nil {
+		t.Fatalf("Root() error = %v", err)
+	}
+	if info.NanosecondHeartbeat != 1760000000123456789 {
+		t.Errorf("Expected exact heartbeat, got %d", info.NanosecondHeartbeat)
+	}
+
+	raw, err := client.RootRaw(context.Background())
+	if err != nil {
+		t.Fatalf("RootRaw() error = %v", err)
+	}
+	if raw["server"] != "chroma" {
+		t.Errorf("Expected string field to be kept, got %v", raw)
+	}
+}
+
+func TestHeartbeatTime(t *testing.T) {
+	// Larger than 2^53, so a float64 round trip would lose the last digits
+	const ns = 1760000000123456789
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"nanosecond heartbeat": 1760000000123456789}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	got, err := client.HeartbeatTime(context.Background())
+	if err != nil {
+		t.Fatalf("HeartbeatTime() error = %v", err)
+	}
+	if got.UnixNano() != ns {
+		t.Errorf("Expected %d, got %d", int64(ns), got.UnixNano())
+	}
+}
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    bool
+	}{
+		{
+			name: "healthy",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"nanosecond heartbeat": 1234567890}`))
+			},
+			want: true,
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			want: false,
+		},
+		{
+			name: "unparseable body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("<html>proxy</html>"))
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			got, err := client.Ping(context.Background())
+			if err != nil {
+				t.Fatalf("Ping() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected Ping() = %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPingServerDown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ok, err := client.Ping(context.Background())
+	if ok || err != nil {
+		t.Errorf("Expected (false, nil) for a down server, got (%v, %v)", ok, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Ping(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWaitForReady(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"nanosecond heartbeat": 1234567890}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if err := client.WaitForReady(context.Background(), time.Millisecond); err != nil {
+		t.Fatalf("WaitForReady() error = %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 heartbeat calls, got %d", calls.Load())
+	}
+
+	if err := client.WaitForReady(context.Background(), 0); err == nil {
+		t.Error("Expected error for zero interval")
+	}
+}
+
+func TestWaitForReadyDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.WaitForReady(ctx, 5*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
 func TestReset(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/reset" {
@@ -167,6 +432,54 @@ func TestGetTenant(t *testing.T) {
 	}
 }
 
+func TestListTenants(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants" {
+			t.Errorf("Expected path /api/v2/tenants, got %s", r.URL.Path)
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Tenant{{Name: "tenant1"}, {Name: "tenant2"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	tenants, err := client.ListTenants(context.Background())
+	if err != nil {
+		t.Fatalf("ListTenants() error = %v", err)
+	}
+	if len(tenants) != 2 {
+		t.Fatalf("Expected 2 tenants, got %d", len(tenants))
+	}
+	if tenants[1].Name != "tenant2" {
+		t.Errorf("Expected second tenant tenant2, got %s", tenants[1].Name)
+	}
+}
+
+func TestListTenantsUnsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	tenants, err := client.ListTenants(context.Background())
+	if err == nil {
+		t.Fatalf("Expected error, got %d tenants", len(tenants))
+	}
+
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		t.Fatalf("Expected HTTPError, got %T", err)
+	}
+	if httpErr.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status code %d, got %d", http.StatusMethodNotAllowed, httpErr.StatusCode)
+	}
+}
+
 func TestCreateDatabase(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/tenants/default_tenant/databases" {
@@ -231,6 +544,131 @@ func TestGetDatabase(t *testing.T) {
 	}
 }
 
+func TestDeleteDatabase(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/test_tenant/databases/test_database" {
+			t.Errorf("Expected path /api/v2/tenants/test_tenant/databases/test_database, got %s", r.URL.Path)
+		}
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected DELETE method, got %s", r.Method)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if err := client.DeleteDatabase(context.Background(), "test_database", "test_tenant"); err != nil {
+		t.Fatalf("DeleteDatabase() error = %v", err)
+	}
+}
+
+func TestDeleteDatabaseNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Database not found"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.DeleteDatabase(context.Background(), "missing_database")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		t.Fatalf("Expected HTTPError, got %T", err)
+	}
+	if httpErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, httpErr.StatusCode)
+	}
+}
+
+func TestListDatabases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/custom_tenant/databases" {
+			t.Errorf("Expected path /api/v2/tenants/custom_tenant/databases, got %s", r.URL.Path)
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+		if r.URL.RawQuery != "" {
+			t.Errorf("Expected no query parameters, got %s", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Database{
+			{ID: "db-1", Name: "database1", Tenant: "custom_tenant"},
+			{ID: "db-2", Name: "database2", Tenant: "custom_tenant"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithTenant("custom_tenant"))
+	databases, err := client.ListDatabases(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ListDatabases() error = %v", err)
+	}
+	if len(databases) != 2 {
+		t.Errorf("Expected 2 databases, got %d", len(databases))
+	}
+}
+
+func TestListDatabasesPaged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/test_tenant/databases" {
+			t.Errorf("Expected path /api/v2/tenants/test_tenant/databases, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("limit"); got != "10" {
+			t.Errorf("Expected limit 10, got %s", got)
+		}
+		if got := r.URL.Query().Get("offset"); got != "20" {
+			t.Errorf("Expected offset 20, got %s", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Database{{ID: "db-21", Name: "database21", Tenant: "test_tenant"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	databases, err := client.ListDatabasesPaged(context.Background(), "test_tenant", 10, 20)
+	if err != nil {
+		t.Fatalf("ListDatabasesPaged() error = %v", err)
+	}
+	if len(databases) != 1 {
+		t.Errorf("Expected 1 database, got %d", len(databases))
+	}
+}
+
+func TestListCollectionsPaged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections" {
+			t.Errorf("Expected collections path, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("limit"); got != "50" {
+			t.Errorf("Expected limit 50, got %s", got)
+		}
+		if got := r.URL.Query().Get("offset"); got != "100" {
+			t.Errorf("Expected offset 100, got %s", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Collection{{ID: "col-101", Name: "collection101"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	collections, err := client.ListCollectionsPaged(context.Background(), "", "", 50, 100)
+	if err != nil {
+		t.Fatalf("ListCollectionsPaged() error = %v", err)
+	}
+	if len(collections) != 1 {
+		t.Errorf("Expected 1 collection, got %d", len(collections))
+	}
+}
+
 func TestListCollections(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections" {
@@ -348,6 +786,53 @@ func TestGetCollection(t *testing.T) {
 	}
 }
 
+func TestGetCollectionConfiguration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "col-123",
+			"name": "test_collection",
+			"configuration_json": {
+				"hnsw": {"space": "cosine", "ef_construction": 100, "ef_search": 50, "max_neighbors": 16,
+					"resize_factor": 1.2, "sync_threshold": 1000},
+				"embedding_function": {"type": "known", "name": "default", "config": {}}
+			},
+			"schema": {"defaults": {"string": {"fts_index": {"enabled": true}}}}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	collection, err := client.GetCollection(context.Background(), "test_collection", "", "")
+	if err != nil {
+		t.Fatalf("GetCollection() error = %v", err)
+	}
+
+	hnsw := collection.ConfigurationJSON.Hnsw
+	if hnsw == nil {
+		t.Fatal("Expected configuration_json.hnsw to be decoded")
+	}
+	if hnsw.Space == nil || *hnsw.Space != SpaceCosine {
+		t.Errorf("Expected space cosine, got %v", hnsw.Space)
+	}
+	if hnsw.EfConstruction == nil || *hnsw.EfConstruction != 100 || hnsw.EfSearch == nil || *hnsw.EfSearch != 50 {
+		t.Errorf("Expected ef_construction 100 and ef_search 50, got %+v", hnsw)
+	}
+	if hnsw.MaxNeighbors == nil || *hnsw.MaxNeighbors != 16 || hnsw.ResizeFactor == nil || *hnsw.ResizeFactor != 1.2 ||
+		hnsw.SyncThreshold == nil || *hnsw.SyncThreshold != 1000 {
+		t.Errorf("Expected the remaining HNSW settings, got %+v", hnsw)
+	}
+	if collection.ConfigurationJSON.Spann != nil {
+		t.Errorf("Expected no SPANN configuration, got %+v", collection.ConfigurationJSON.Spann)
+	}
+	if ef := collection.ConfigurationJSON.EmbeddingFunction; ef == nil || ef.Name != "default" {
+		t.Errorf("Expected embedding function default, got %+v", ef)
+	}
+	if collection.Schema == nil || collection.Schema.Defaults.String == nil {
+		t.Errorf("Expected the schema to be decoded, got %+v", collection.Schema)
+	}
+}
+
 func TestDeleteCollection(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/test_collection" {
@@ -372,29 +857,198 @@ func TestDeleteCollection(t *testing.T) {
 	}
 }
 
-func TestUpdateCollection(t *testing.T) {
+func TestGetOrCreateCollection(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123" {
-			t.Errorf("Expected path /api/v2/tenants/default_tenant/databases/default_database/collections/col-123, got %s", r.URL.Path)
-		}
-		if r.Method != http.MethodPut {
-			t.Errorf("Expected PUT method, got %s", r.Method)
+		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
 		}
-
-		var req UpdateCollection
+		var req CreateCollection
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			t.Fatalf("Failed to decode request body: %v", err)
 		}
-		if req.NewName == nil || *req.NewName != "updated_collection" {
-			t.Errorf("Expected new name updated_collection")
+		if !req.GetOrCreate {
+			t.Errorf("Expected get_or_create to be set")
 		}
-
-		w.WriteHeader(http.StatusOK)
+		if req.Metadata["description"] != "docs" {
+			t.Errorf("Expected metadata to be sent, got %v", req.Metadata)
+		}
+		json.NewEncoder(w).Encode(Collection{ID: "col-123", Name: req.Name})
 	}))
 	defer server.Close()
 
 	client := NewClient(WithBaseURL(server.URL))
-	newName := "updated_collection"
+	collection, err := client.GetOrCreateCollection(context.Background(), "docs", map[string]interface{}{"description": "docs"}, "", "")
+	if err != nil {
+		t.Fatalf("GetOrCreateCollection() error = %v", err)
+	}
+	if collection.ID != "col-123" || collection.Name != "docs" {
+		t.Errorf("Unexpected collection %+v", collection)
+	}
+}
+
+func TestCreateCollectionConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error":"UniqueConstraintError","message":"Collection docs already exists"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	_, err := client.CreateCollection(context.Background(), CreateCollection{Name: "docs"}, "", "")
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected ErrConflict, got %v", err)
+	}
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusConflict {
+		t.Errorf("Expected the HTTPError to remain available, got %v", err)
+	}
+
+	if errors.Is(&HTTPError{StatusCode: http.StatusNotFound}, ErrConflict) {
+		t.Error("Expected a 404 not to match ErrConflict")
+	}
+}
+
+func TestPathEscaping(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+	client.GetCollection(ctx, "my docs ü", "", "")
+	client.DeleteCollection(ctx, "a/b", "", "")
+	client.GetDatabase(ctx, "team db", "acme corp")
+
+	want := []string{
+		"/api/v2/tenants/default_tenant/databases/default_database/collections/my%20docs%20%C3%BC",
+		"/api/v2/tenants/default_tenant/databases/default_database/collections/a%2Fb",
+		"/api/v2/tenants/acme%20corp/databases/team%20db",
+	}
+	if fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Errorf("Expected paths %v, got %v", want, paths)
+	}
+}
+
+func TestCreateCollectionWithSpace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CreateCollection
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if req.Name != "cosine_collection" {
+			t.Errorf("Expected name cosine_collection, got %s", req.Name)
+		}
+		if !req.GetOrCreate {
+			t.Errorf("Expected get_or_create to be set")
+		}
+		if req.Configuration == nil || req.Configuration.Hnsw == nil || req.Configuration.Hnsw.Space == nil {
+			t.Fatalf("Expected configuration.hnsw.space to be set")
+		}
+		if *req.Configuration.Hnsw.Space != SpaceCosine {
+			t.Errorf("Expected space cosine, got %s", *req.Configuration.Hnsw.Space)
+		}
+
+		json.NewEncoder(w).Encode(Collection{ID: "col-123", Name: req.Name})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	collection, err := client.CreateCollectionWithSpace(context.Background(), "cosine_collection", SpaceCosine, "", "")
+	if err != nil {
+		t.Fatalf("CreateCollectionWithSpace() error = %v", err)
+	}
+	if collection.ID != "col-123" {
+		t.Errorf("Expected collection ID col-123, got %s", collection.ID)
+	}
+
+	_, err = client.CreateCollectionWithSpace(context.Background(), "typo", Space("cosign"), "", "")
+	if err == nil || !strings.Contains(err.Error(), "invalid space") {
+		t.Errorf("Expected invalid space error, got %v", err)
+	}
+}
+
+func TestCreateCollectionWithSpann(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if got := string(body["configuration"]); got != `{"spann":{"search_nprobe":64,"space":"ip"}}` {
+			t.Errorf("Unexpected configuration %s", got)
+		}
+		json.NewEncoder(w).Encode(Collection{ID: "col-123", Name: "spann_collection"})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	nprobe := int32(64)
+	space := SpaceIP
+	collection, err := client.CreateCollectionWithSpann(context.Background(), "spann_collection",
+		SpannConfiguration{SearchNprobe: &nprobe, Space: &space}, "", "")
+	if err != nil {
+		t.Fatalf("CreateCollectionWithSpann() error = %v", err)
+	}
+	if collection.ID != "col-123" {
+		t.Errorf("Expected collection ID col-123, got %s", collection.ID)
+	}
+
+	typo := Space("cosign")
+	_, err = client.CreateCollectionWithSpann(context.Background(), "typo", SpannConfiguration{Space: &typo}, "", "")
+	if err == nil || !strings.Contains(err.Error(), `spann space "cosign"`) {
+		t.Errorf("Expected invalid space error, got %v", err)
+	}
+}
+
+func TestGetCollectionByID(t *testing.T) {
+	const id = "8c0a2b4e-6f1d-4e8a-9b3c-2d7e5f6a1b0c"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "/api/v2/tenants/default_tenant/databases/default_database/collections/" + id
+		if r.URL.Path != want {
+			t.Errorf("Expected path %s, got %s", want, r.URL.Path)
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+		json.NewEncoder(w).Encode(Collection{ID: id, Name: "test_collection"})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	collection, err := client.GetCollectionByID(context.Background(), id, "", "")
+	if err != nil {
+		t.Fatalf("GetCollectionByID() error = %v", err)
+	}
+	if collection.Name != "test_collection" {
+		t.Errorf("Expected collection name test_collection, got %s", collection.Name)
+	}
+}
+
+func TestUpdateCollection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123" {
+			t.Errorf("Expected path /api/v2/tenants/default_tenant/databases/default_database/collections/col-123, got %s", r.URL.Path)
+		}
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT method, got %s", r.Method)
+		}
+
+		var req UpdateCollection
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if req.NewName == nil || *req.NewName != "updated_collection" {
+			t.Errorf("Expected new name updated_collection")
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	newName := "updated_collection"
 	err := client.UpdateCollection(context.Background(), "col-123", UpdateCollection{
 		NewName: &newName,
 	}, "", "")
@@ -403,6 +1057,229 @@ func TestUpdateCollection(t *testing.T) {
 	}
 }
 
+func TestUpdateCollectionScoped(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/acme/databases/prod/collections/col-123" {
+			t.Errorf("Expected scoped path for acme/prod, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	newName := "updated_collection"
+	err := client.UpdateCollection(context.Background(), "col-123", UpdateCollection{
+		NewName: &newName,
+	}, "acme", "prod")
+	if err != nil {
+		t.Fatalf("UpdateCollection() error = %v", err)
+	}
+}
+
+func TestRenameCollection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := "/api/v2/tenants/acme/databases/prod/collections/col-123"
+		if r.URL.Path != path {
+			t.Errorf("Expected path %s, got %s", path, r.URL.Path)
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if want := `{"new_name":"renamed"}`; string(body) != want {
+				t.Errorf("Expected body %s, got %s", want, body)
+			}
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(Collection{ID: "col-123", Name: "renamed"})
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	collection, err := client.RenameCollection(context.Background(), "col-123", "renamed", "acme", "prod")
+	if err != nil {
+		t.Fatalf("RenameCollection() error = %v", err)
+	}
+	if collection.Name != "renamed" {
+		t.Errorf("Expected name renamed, got %s", collection.Name)
+	}
+
+	if _, err := client.RenameCollection(context.Background(), "col-123", "", "", ""); err == nil {
+		t.Error("Expected error for empty name")
+	}
+}
+
+func TestUpdateHNSW(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123"
+		if r.URL.Path != path {
+			t.Errorf("Expected path %s, got %s", path, r.URL.Path)
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			want := `{"new_configuration":{"hnsw":{"ef_search":200}}}`
+			if string(body) != want {
+				t.Errorf("Expected body %s, got %s", want, body)
+			}
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(Collection{ID: "col-123", Name: "test_collection"})
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	efSearch := 200
+	collection, err := client.UpdateHNSW(context.Background(), "col-123", HnswConfiguration{
+		EfSearch: &efSearch,
+	}, "", "")
+	if err != nil {
+		t.Fatalf("UpdateHNSW() error = %v", err)
+	}
+	if collection.ID != "col-123" {
+		t.Errorf("Expected collection ID col-123, got %s", collection.ID)
+	}
+}
+
+func TestUpdateHNSWRejectsCreationOnlySettings(t *testing.T) {
+	client := NewClient(WithBaseURL("http://127.0.0.1:0"))
+	space := SpaceCosine
+	_, err := client.UpdateHNSW(context.Background(), "col-123", HnswConfiguration{
+		Space: &space,
+	}, "", "")
+	if err == nil || !strings.Contains(err.Error(), "invalid configuration") {
+		t.Errorf("Expected invalid configuration error, got %v", err)
+	}
+}
+
+func TestResolveCollection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Collection{ID: "col-123", Name: "renamed"})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	col, err := client.ResolveCollection(context.Background(), "col-123", "renamed", "", "")
+	if err != nil || col.ID != "col-123" {
+		t.Fatalf("ResolveCollection() = %v, %v", col, err)
+	}
+
+	_, err = client.ResolveCollection(context.Background(), "col-123", "docs", "", "")
+	if err == nil || !strings.Contains(err.Error(), `named "renamed", expected "docs"`) {
+		t.Errorf("Expected name mismatch error, got %v", err)
+	}
+}
+
+func TestSetEmbeddingFunction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			want := `{"new_configuration":{"embedding_function":{"type":"known","name":"openai","config":{"model_name":"text-embedding-3-small"}}}}`
+			if string(body) != want {
+				t.Errorf("Expected body %s, got %s", want, body)
+			}
+			if r.URL.Path == "/api/v2/tenants/default_tenant/databases/default_database/collections/locked" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"InvalidArgumentError","message":"cannot change embedding function"}`))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(Collection{ID: "col-123", Name: "test_collection"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	cfg := EmbeddingFunctionConfiguration{
+		Type:   "known",
+		Name:   "openai",
+		Config: map[string]interface{}{"model_name": "text-embedding-3-small"},
+	}
+	collection, err := client.SetEmbeddingFunction(context.Background(), "col-123", cfg, "", "")
+	if err != nil {
+		t.Fatalf("SetEmbeddingFunction() error = %v", err)
+	}
+	if collection.ID != "col-123" {
+		t.Errorf("Expected collection ID col-123, got %s", collection.ID)
+	}
+
+	_, err = client.SetEmbeddingFunction(context.Background(), "locked", cfg, "", "")
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected HTTP 400 error, got %v", err)
+	}
+
+	_, err = client.SetEmbeddingFunction(context.Background(), "col-123", EmbeddingFunctionConfiguration{}, "", "")
+	if err == nil || !strings.Contains(err.Error(), "type is required") {
+		t.Errorf("Expected missing type error, got %v", err)
+	}
+}
+
+func TestUpdateMetadataMerge(t *testing.T) {
+	var updated string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/get"):
+			var req GetEmbedding
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("Failed to decode request body: %v", err)
+			}
+			if fmt.Sprint(req.IDs) != "[id1 id2]" {
+				t.Errorf("Expected ids [id1 id2], got %v", req.IDs)
+			}
+			// Returned out of order to check that rows are matched by ID
+			w.Write([]byte(`{"ids": ["id2", "id1"], "metadatas": [{"read": false}, {"read": false, "tag": "a"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/update"):
+			body, _ := io.ReadAll(r.Body)
+			updated = string(body)
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.UpdateMetadataMerge(context.Background(), "col-123", []string{"id1", "id2"}, []map[string]interface{}{
+		{"read": true, "tag": nil},
+		{"score": 3},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("UpdateMetadataMerge() error = %v", err)
+	}
+
+	want := `{"ids":["id1","id2"],"metadatas":[{"read":true,"tag":null},{"read":false,"score":3}]}`
+	if updated != want {
+		t.Errorf("Expected update body %s, got %s", want, updated)
+	}
+}
+
+func TestUpdateMetadataMergeMissingID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/update") {
+			t.Error("Expected no update when an ID is missing")
+		}
+		w.Write([]byte(`{"ids": ["id1"], "metadatas": [{}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.UpdateMetadataMerge(context.Background(), "col-123", []string{"id1", "gone"}, []map[string]interface{}{
+		{"k": 1}, {"k": 2},
+	}, "", "")
+	if err == nil || !strings.Contains(err.Error(), "ids not found: gone") {
+		t.Errorf("Expected missing id error, got %v", err)
+	}
+}
+
 func TestAdd(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123/add" {
@@ -496,6 +1373,132 @@ func TestQuery(t *testing.T) {
 	}
 }
 
+func TestDocumentOperationsScopedPaths(t *testing.T) {
+	const prefix = "/api/v2/tenants/tenant_a/databases/db_b/collections/col-123"
+
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/count") {
+			w.Write([]byte("0"))
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(WithBaseURL(server.URL))
+	scoped := NewClient(WithBaseURL(server.URL), WithTenant("tenant_a"), WithDatabase("db_b"))
+
+	tests := []struct {
+		name string
+		want string
+		call func() error
+	}{
+		{"Add", prefix + "/add", func() error {
+			return client.Add(ctx, "col-123", AddEmbedding{IDs: []string{"id1"}, Documents: []string{"doc1"}}, "tenant_a", "db_b")
+		}},
+		{"Update", prefix + "/update", func() error {
+			return client.Update(ctx, "col-123", UpdateEmbedding{IDs: []string{"id1"}}, "tenant_a", "db_b")
+		}},
+		{"Upsert", prefix + "/upsert", func() error {
+			return client.Upsert(ctx, "col-123", AddEmbedding{IDs: []string{"id1"}, Documents: []string{"doc1"}}, "tenant_a", "db_b")
+		}},
+		{"Get", prefix + "/get", func() error {
+			_, err := client.Get(ctx, "col-123", GetEmbedding{}, "tenant_a", "db_b")
+			return err
+		}},
+		{"Delete", prefix + "/delete", func() error {
+			return client.Delete(ctx, "col-123", DeleteEmbedding{IDs: []string{"id1"}}, "tenant_a", "db_b")
+		}},
+		{"Query", prefix + "/query", func() error {
+			_, err := client.Query(ctx, "col-123", QueryEmbedding{QueryEmbeddings: [][]float64{{0.1}}}, "tenant_a", "db_b")
+			return err
+		}},
+		{"Count", prefix + "/count", func() error {
+			_, err := client.Count(ctx, "col-123", "tenant_a", "db_b")
+			return err
+		}},
+		{"CountDefaulted", prefix + "/count", func() error {
+			_, err := scoped.Count(ctx, "col-123", "", "")
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			if gotPath != tt.want {
+				t.Errorf("Expected path %s, got %s", tt.want, gotPath)
+			}
+		})
+	}
+}
+
+func TestQueryByText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123/query" {
+			t.Errorf("Expected path /api/v2/tenants/default_tenant/databases/default_database/collections/col-123/query, got %s", r.URL.Path)
+		}
+
+		var req map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if got := string(req["query_texts"]); got != `["hello world"]` {
+			t.Errorf("Expected query_texts [\"hello world\"], got %s", got)
+		}
+		if _, ok := req["query_embeddings"]; ok {
+			t.Errorf("Expected query_embeddings to be omitted, got %s", req["query_embeddings"])
+		}
+		if got := string(req["n_results"]); got != "3" {
+			t.Errorf("Expected n_results 3, got %s", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(QueryResult{IDs: [][]string{{"id1"}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	result, err := client.QueryByText(context.Background(), "col-123", []string{"hello world"}, QueryEmbedding{NResults: 3}, "", "")
+	if err != nil {
+		t.Fatalf("QueryByText() error = %v", err)
+	}
+	if len(result.IDs) != 1 {
+		t.Errorf("Expected 1 result group, got %d", len(result.IDs))
+	}
+}
+
+func TestQueryValidation(t *testing.T) {
+	client := NewClient(WithBaseURL("http://127.0.0.1:0"))
+
+	tests := []struct {
+		name string
+		req  QueryEmbedding
+		want string
+	}{
+		{"both", QueryEmbedding{QueryEmbeddings: [][]float64{{0.1}}, QueryTexts: []string{"text"}}, "not both"},
+		{"neither", QueryEmbedding{NResults: 5}, "is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Query(context.Background(), "col-123", tt.req, "", "")
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
 func TestHTTPError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -517,3 +1520,575 @@ func TestHTTPError(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, httpErr.StatusCode)
 	}
 }
+
+func TestSlowRequestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/heartbeat" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode("0.4.24")
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+		WithSlowRequestThreshold(20*time.Millisecond),
+	)
+
+	if _, err := client.Version(context.Background()); err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no log output for a fast request, got %q", buf.String())
+	}
+
+	client.Heartbeat(context.Background())
+	out := buf.String()
+	if !strings.Contains(out, "slow chroma request") {
+		t.Fatalf("Expected slow request to be logged, got %q", out)
+	}
+	if !strings.Contains(out, "method=GET") || !strings.Contains(out, "path=/api/v2/heartbeat") {
+		t.Errorf("Expected method and path in log output, got %q", out)
+	}
+	if !strings.Contains(out, "duration=") {
+		t.Errorf("Expected duration in log output, got %q", out)
+	}
+}
+
+func TestSlowRequestThresholdWithoutLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode("0.4.24")
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithSlowRequestThreshold(time.Nanosecond))
+	if _, err := client.Version(context.Background()); err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+}
+
+func TestWithTimeoutDoesNotModifyHTTPClient(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Minute}
+	client := NewClient(WithHTTPClient(httpClient), WithTimeout(5*time.Second))
+
+	if client.timeout != 5*time.Second {
+		t.Errorf("Expected client timeout 5s, got %v", client.timeout)
+	}
+	if client.httpClient.Timeout != 0 {
+		t.Errorf("Expected timeout to move to request contexts, got HTTP client timeout %v", client.httpClient.Timeout)
+	}
+	if httpClient.Timeout != time.Minute {
+		t.Errorf("Expected caller's HTTP client to keep its timeout, got %v", httpClient.Timeout)
+	}
+}
+
+func TestWithResponseInspector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "41")
+		if r.URL.Path == "/api/v2/version" {
+			w.Write([]byte(`"1.0.0"`))
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("slow down"))
+	}))
+	defer server.Close()
+
+	var (
+		statuses  []int
+		remaining []string
+		bodies    []string
+	)
+	client := NewClient(WithBaseURL(server.URL), WithResponseInspector(func(resp *http.Response) {
+		statuses = append(statuses, resp.StatusCode)
+		remaining = append(remaining, resp.Header.Get("X-RateLimit-Remaining"))
+		body, _ := io.ReadAll(resp.Body)
+		bodies = append(bodies, string(body))
+		resp.Header.Set("X-RateLimit-Remaining", "changed")
+	}))
+
+	version, err := client.Version(context.Background())
+	if err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if version != "1.0.0" {
+		t.Errorf("Expected version 1.0.0 after inspection, got %s", version)
+	}
+	if _, err := client.Heartbeat(context.Background()); err == nil {
+		t.Fatal("Expected error for 429 response")
+	}
+
+	if fmt.Sprint(statuses) != "[200 429]" {
+		t.Errorf("Expected statuses [200 429], got %v", statuses)
+	}
+	if fmt.Sprint(remaining) != "[41 41]" {
+		t.Errorf("Expected rate-limit header 41 on both responses, got %v", remaining)
+	}
+	if bodies[1] != "slow down" {
+		t.Errorf("Expected error body to be readable, got %q", bodies[1])
+	}
+}
+
+func TestContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			w.Write([]byte(`"1.0.0"`))
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		clientTimeout time.Duration
+		ctxTimeout    time.Duration
+		wantErr       bool
+	}{
+		{name: "shorter context deadline", clientTimeout: 5 * time.Second, ctxTimeout: 50 * time.Millisecond, wantErr: true},
+		{name: "longer context deadline", clientTimeout: 50 * time.Millisecond, ctxTimeout: 5 * time.Second},
+		{name: "client timeout without deadline", clientTimeout: 50 * time.Millisecond, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(WithBaseURL(server.URL), WithTimeout(tt.clientTimeout))
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTimeout)
+				defer cancel()
+			}
+
+			_, err := client.Version(ctx)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Version() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"1.0.0"`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Version(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if err.Error() != "request interrupted: context canceled" {
+		t.Errorf("Expected cancellation to be reported plainly, got %q", err.Error())
+	}
+}
+
+func TestWithAPIVersionV1(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/tenants/default_tenant/databases/default_database/collections/col-123/count" {
+			t.Errorf("Expected v1 path, got %s", r.URL.Path)
+		}
+		w.Write([]byte("7"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithAPIVersion(APIVersionV1))
+	if _, err := client.Count(context.Background(), "col-123", "", ""); err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+}
+
+func TestWithAPIVersionInvalid(t *testing.T) {
+	client := NewClient(WithAPIVersion("v3"))
+	_, err := client.Version(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "v3") {
+		t.Errorf("Expected unsupported version error, got %v", err)
+	}
+}
+
+func TestDetectAPIVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		supported string
+		want      string
+	}{
+		{"v2 server", "/api/v2/heartbeat", APIVersionV2},
+		{"v1 server", "/api/v1/heartbeat", APIVersionV1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.supported {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(`{"nanosecond heartbeat": 1}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			got, err := client.DetectAPIVersion(context.Background())
+			if err != nil {
+				t.Fatalf("DetectAPIVersion() error = %v", err)
+			}
+			if got != tt.want || client.apiVersion != tt.want {
+				t.Errorf("Expected version %s, got %s (client uses %s)", tt.want, got, client.apiVersion)
+			}
+		})
+	}
+}
+
+func TestDetectAPIVersionServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if _, err := client.DetectAPIVersion(context.Background()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if client.apiVersion != APIVersionV2 {
+		t.Errorf("Expected version to stay v2, got %s", client.apiVersion)
+	}
+}
+
+func TestPeek(t *testing.T) {
+	tests := []struct {
+		n     int
+		limit int
+	}{
+		{3, 3},
+		{0, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123/get" {
+					t.Errorf("Expected get path, got %s", r.URL.Path)
+				}
+				var req GetEmbedding
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("Failed to decode request body: %v", err)
+				}
+				if req.Limit == nil || *req.Limit != tt.limit {
+					t.Errorf("Expected limit %d, got %v", tt.limit, req.Limit)
+				}
+				if len(req.Include) != 2 || req.Include[0] != IncludeDocuments || req.Include[1] != IncludeMetadatas {
+					t.Errorf("Expected documents and metadatas, got %v", req.Include)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"ids": ["a"], "documents": ["doc a"]}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			result, err := client.Peek(context.Background(), "col-123", tt.n, "", "")
+			if err != nil {
+				t.Fatalf("Peek() error = %v", err)
+			}
+			if len(result.IDs) != 1 || result.Documents[0] != "doc a" {
+				t.Errorf("Unexpected result %+v", result)
+			}
+		})
+	}
+}
+
+func TestCollectionExists(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		want    bool
+		wantErr bool
+	}{
+		{"exists", http.StatusOK, true, false},
+		{"missing", http.StatusNotFound, false, false},
+		{"server error", http.StatusInternalServerError, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/docs" {
+					t.Errorf("Expected collection path, got %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				if tt.status == http.StatusOK {
+					w.Write([]byte(`{"id": "col-123", "name": "docs"}`))
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			got, err := client.CollectionExists(context.Background(), "docs", "", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CollectionExists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDeleteDryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123/get" {
+			t.Errorf("Expected get path, got %s", r.URL.Path)
+		}
+		var req map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if string(req["ids"]) != `["a","b","x"]` || string(req["where"]) != `{"category":"old"}` || string(req["where_document"]) != `{"$contains":"draft"}` {
+			t.Errorf("Expected delete filters to be forwarded, got %s", req)
+		}
+		if string(req["include"]) != "[]" || string(req["limit"]) != strconv.Itoa(countPageSize) {
+			t.Errorf("Expected an IDs-only page, got include %s and limit %s", req["include"], req["limit"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ids": ["a", "b"]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithDefaultInclude(IncludeEmbeddings))
+	n, err := client.DeleteDryRun(context.Background(), "col-123", DeleteEmbedding{
+		IDs:           []string{"a", "b", "x"},
+		Where:         map[string]interface{}{"category": "old"},
+		WhereDocument: map[string]interface{}{"$contains": "draft"},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("DeleteDryRun() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2, got %d", n)
+	}
+}
+
+func TestGetByIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []Include
+		want    []Include
+	}{
+		{"default include", nil, []Include{IncludeDocuments, IncludeMetadatas}},
+		{"explicit include", []Include{IncludeEmbeddings}, []Include{IncludeEmbeddings}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123/get" {
+					t.Errorf("Expected get path, got %s", r.URL.Path)
+				}
+				var req GetEmbedding
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("Failed to decode request body: %v", err)
+				}
+				if fmt.Sprint(req.IDs) != "[a b]" {
+					t.Errorf("Expected ids [a b], got %v", req.IDs)
+				}
+				if fmt.Sprint(req.Include) != fmt.Sprint(tt.want) {
+					t.Errorf("Expected include %v, got %v", tt.want, req.Include)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"ids": ["a", "b"]}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			result, err := client.GetByIDs(context.Background(), "col-123", []string{"a", "b"}, "", "", tt.include...)
+			if err != nil {
+				t.Fatalf("GetByIDs() error = %v", err)
+			}
+			if len(result.IDs) != 2 {
+				t.Errorf("Expected 2 ids, got %v", result.IDs)
+			}
+		})
+	}
+}
+
+func TestMaxBatchSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    int
+		wantErr string
+	}{
+		{"reported", `{"max_batch_size": 5461}`, 5461, ""},
+		{"missing", `{}`, 0, "did not report max_batch_size"},
+		{"wrong type", `{"max_batch_size": "lots"}`, 0, "invalid max_batch_size"},
+		{"fractional", `{"max_batch_size": 1.5}`, 0, "invalid max_batch_size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v2/pre-flight-checks" {
+					t.Errorf("Expected path /api/v2/pre-flight-checks, got %s", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			got, err := client.MaxBatchSize(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MaxBatchSize() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQueryStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ids": [["a", "b"], ["c"]], "distances": [[0.1, 0.2], [0.3]]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	req := QueryEmbedding{QueryEmbeddings: [][]float64{{1}, {2}}, NResults: 2}
+	var got []string
+	err := client.QueryStream(context.Background(), "col-123", req, func(query int, hit QueryHit) error {
+		got = append(got, fmt.Sprintf("%d:%s:%v", query, hit.ID, hit.Distance))
+		return nil
+	}, "", "")
+	if err != nil {
+		t.Fatalf("QueryStream() error = %v", err)
+	}
+	if fmt.Sprint(got) != "[0:a:0.1 0:b:0.2 1:c:0.3]" {
+		t.Errorf("Unexpected hits %v", got)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = client.QueryStream(context.Background(), "col-123", req, func(int, QueryHit) error {
+		calls++
+		return stop
+	}, "", "")
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Expected to stop after the first hit, got %v after %d calls", err, calls)
+	}
+}
+
+func TestDefaultInclude(t *testing.T) {
+	var include []Include
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Include []Include `json:"include"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		include = body.Include
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ids": []}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL),
+		WithDefaultInclude(IncludeDocuments, IncludeMetadatas, IncludeDistances))
+	ctx := context.Background()
+	query := QueryEmbedding{QueryEmbeddings: [][]float64{{1}}}
+
+	if _, err := client.Query(ctx, "c", query, "", ""); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if want := Includes(IncludeDocuments, IncludeMetadatas, IncludeDistances); !slices.Equal(include, want) {
+		t.Errorf("Expected default Include %v for a nil Include, got %v", want, include)
+	}
+
+	query.Include = []Include{}
+	if _, err := client.Query(ctx, "c", query, "", ""); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(include) != 3 {
+		t.Errorf("Expected default Include for an empty Include, got %v", include)
+	}
+
+	query.Include = Includes(IncludeEmbeddings)
+	if _, err := client.Query(ctx, "c", query, "", ""); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if !slices.Equal(include, Includes(IncludeEmbeddings)) {
+		t.Errorf("Expected explicit Include to override the default, got %v", include)
+	}
+
+	if _, err := client.Get(ctx, "c", GetEmbedding{}, "", ""); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if want := Includes(IncludeDocuments, IncludeMetadatas); !slices.Equal(include, want) {
+		t.Errorf("Expected Get default %v without distances, got %v", want, include)
+	}
+
+	client = NewClient(WithBaseURL(server.URL))
+	if _, err := client.Get(ctx, "c", GetEmbedding{}, "", ""); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if include != nil {
+		t.Errorf("Expected no Include without a default, got %v", include)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	versionFails := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/heartbeat":
+			w.Write([]byte(`{"nanosecond heartbeat": 1234567890}`))
+		case "/api/v2/version":
+			if versionFails {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error": "boom"}`))
+				return
+			}
+			w.Write([]byte(`"1.0.0"`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	status, err := client.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if want := (ServerStatus{Healthy: true, Version: "1.0.0", NanosecondHeartbeat: 1234567890}); status != want {
+		t.Errorf("Expected %+v, got %+v", want, status)
+	}
+
+	versionFails = true
+	status, err = client.Status(context.Background())
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || !strings.Contains(err.Error(), "failed to get version") {
+		t.Errorf("Expected version error, got %v", err)
+	}
+	if !status.Healthy || status.NanosecondHeartbeat != 1234567890 || status.Version != "" {
+		t.Errorf("Expected partial status with the heartbeat, got %+v", status)
+	}
+}
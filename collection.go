@@ -0,0 +1,225 @@
+package chromaclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// CollectionHandle is a collection bound to a client, tenant and database, so
+// document operations need not repeat the collection ID and scope. Create one
+// with Client.Collection.
+type CollectionHandle struct {
+	client    *Client
+	id        string
+	name      string
+	tenant    string
+	database  string
+	dimension *int32
+	space     Space
+
+	// embeddingFunction is the collection's configured embedding function,
+	// nil if it has none
+	embeddingFunction *EmbeddingFunctionConfiguration
+}
+
+// WithDimensionValidation controls whether CollectionHandle methods check
+// embedding lengths against the collection's dimension before sending. It is
+// on by default; turn it off to leave the check to the server.
+func WithDimensionValidation(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.skipDimensionCheck = !enabled
+	}
+}
+
+// Collection resolves the named collection once and returns a handle for it.
+// Empty tenant and database use the client defaults.
+func (c *Client) Collection(ctx context.Context, name string, tenant, database string) (*CollectionHandle, error) {
+	if tenant == "" {
+		tenant = c.tenant
+	}
+	if database == "" {
+		database = c.database
+	}
+
+	col, err := c.GetCollection(ctx, name, tenant, database)
+	if err != nil {
+		return nil, err
+	}
+	return &CollectionHandle{
+		client:    c,
+		id:        col.ID,
+		name:      col.Name,
+		tenant:    tenant,
+		database:  database,
+		dimension: col.Dimension,
+		space:     col.Space(),
+
+		embeddingFunction: col.ConfigurationJSON.EmbeddingFunction,
+	}, nil
+}
+
+// Space returns the distance space configured for the collection, from its
+// HNSW or SPANN configuration or its legacy hnsw:space metadata, defaulting
+// to SpaceL2 as the server does
+func (col *Collection) Space() Space {
+	cfg := col.ConfigurationJSON
+	if cfg.Hnsw != nil && cfg.Hnsw.Space != nil {
+		return *cfg.Hnsw.Space
+	}
+	if cfg.Spann != nil && cfg.Spann.Space != nil {
+		return *cfg.Spann.Space
+	}
+	if s, ok := col.Metadata["hnsw:space"].(string); ok && s != "" {
+		return Space(s)
+	}
+	return SpaceL2
+}
+
+// ID returns the collection ID
+func (h *CollectionHandle) ID() string {
+	return h.id
+}
+
+// Name returns the collection name
+func (h *CollectionHandle) Name() string {
+	return h.name
+}
+
+// Dimension returns the collection's embedding dimension. It reports false if
+// the server has not fixed one yet, which happens before the first add.
+func (h *CollectionHandle) Dimension() (int, bool) {
+	if h.dimension == nil {
+		return 0, false
+	}
+	return int(*h.dimension), true
+}
+
+// Space returns the collection's distance space
+func (h *CollectionHandle) Space() Space {
+	return h.space
+}
+
+// checkDimensions checks embeddings against the collection's dimension, if
+// it is known and validation is enabled
+func (h *CollectionHandle) checkDimensions(what string, embeddings [][]float64) error {
+	dim, ok := h.Dimension()
+	if !ok || h.client.skipDimensionCheck {
+		return nil
+	}
+	return checkDimensions(what, embeddings, dim)
+}
+
+// Add adds embeddings to the collection. Documents are embedded with the
+// client's embedding function first, so its vectors are checked against the
+// collection's dimension too.
+func (h *CollectionHandle) Add(ctx context.Context, req AddEmbedding) error {
+	req, err := h.client.embedDocuments(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := h.checkDimensions("embedding", req.Embeddings); err != nil {
+		return fmt.Errorf("invalid records: %w", err)
+	}
+	return h.client.Add(ctx, h.id, req, h.tenant, h.database)
+}
+
+// Upsert upserts embeddings in the collection, embedding documents first as
+// Add does
+func (h *CollectionHandle) Upsert(ctx context.Context, req AddEmbedding) error {
+	req, err := h.client.embedDocuments(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := h.checkDimensions("embedding", req.Embeddings); err != nil {
+		return fmt.Errorf("invalid records: %w", err)
+	}
+	return h.client.Upsert(ctx, h.id, req, h.tenant, h.database)
+}
+
+// Update updates embeddings in the collection
+func (h *CollectionHandle) Update(ctx context.Context, req UpdateEmbedding) error {
+	if err := h.checkDimensions("embedding", req.Embeddings); err != nil {
+		return fmt.Errorf("invalid records: %w", err)
+	}
+	return h.client.Update(ctx, h.id, req, h.tenant, h.database)
+}
+
+// Get gets embeddings from the collection
+func (h *CollectionHandle) Get(ctx context.Context, req GetEmbedding) (*GetResult, error) {
+	return h.client.Get(ctx, h.id, req, h.tenant, h.database)
+}
+
+// GetByIDs gets the records with the given IDs; see Client.GetByIDs
+func (h *CollectionHandle) GetByIDs(ctx context.Context, ids []string, include ...Include) (*GetResult, error) {
+	return h.client.GetByIDs(ctx, h.id, ids, h.tenant, h.database, include...)
+}
+
+// Peek returns the first n records of the collection; see Client.Peek
+func (h *CollectionHandle) Peek(ctx context.Context, n int) (*GetResult, error) {
+	return h.client.Peek(ctx, h.id, n, h.tenant, h.database)
+}
+
+// Delete deletes embeddings from the collection
+func (h *CollectionHandle) Delete(ctx context.Context, req DeleteEmbedding) error {
+	return h.client.Delete(ctx, h.id, req, h.tenant, h.database)
+}
+
+// Count returns the number of embeddings in the collection
+func (h *CollectionHandle) Count(ctx context.Context) (int, error) {
+	return h.client.Count(ctx, h.id, h.tenant, h.database)
+}
+
+// Query queries the collection for nearest neighbors. Query texts are
+// embedded with the client's embedding function first, so its vectors are
+// checked against the collection's dimension too.
+func (h *CollectionHandle) Query(ctx context.Context, req QueryEmbedding) (*QueryResult, error) {
+	req, err := h.client.embedQueryTexts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.checkDimensions("query embedding", req.QueryEmbeddings); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	return h.client.Query(ctx, h.id, req, h.tenant, h.database)
+}
+
+// QueryByText queries the collection with raw texts, embedding them with the
+// collection's own model. With a client embedding function the texts are
+// embedded client-side, and it is an error if the function's Model differs
+// from the model in the collection's embedding_function configuration.
+// Without one they are sent as query_texts for the server to embed, which
+// requires the collection to have a known embedding function configured.
+// Other query parameters are taken from req.
+func (h *CollectionHandle) QueryByText(ctx context.Context, texts []string, req QueryEmbedding) (*QueryResult, error) {
+	if ef := h.client.embeddingFunction; ef != nil {
+		want := configuredModel(h.embeddingFunction)
+		if got, ok := embeddingModel(ef); ok && want != "" && got != want {
+			return nil, fmt.Errorf("invalid query: collection %s embeds with model %q, client embedding function uses %q", h.name, want, got)
+		}
+	} else if !serverSideEmbedding(h.embeddingFunction) {
+		return nil, fmt.Errorf("invalid query: collection %s has no embedding function the server can run and the client has none; pass query embeddings or use WithEmbeddingFunction", h.name)
+	}
+	req.QueryTexts = texts
+	return h.Query(ctx, req)
+}
+
+// serverSideEmbedding reports whether cfg names an embedding function the
+// server can run. Only "known" functions can; "unknown" and "legacy"
+// configurations only record that embeddings were computed by a client.
+func serverSideEmbedding(cfg *EmbeddingFunctionConfiguration) bool {
+	return cfg != nil && cfg.Type == "known"
+}
+
+// configuredModel returns the model name recorded in an embedding function
+// configuration, or "" if it records none
+func configuredModel(cfg *EmbeddingFunctionConfiguration) string {
+	if cfg == nil {
+		return ""
+	}
+	for _, key := range []string{"model_name", "model"} {
+		if model, ok := cfg.Config[key].(string); ok && model != "" {
+			return model
+		}
+	}
+	return ""
+}
@@ -0,0 +1,281 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCollectionHandle(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/tenants/acme/databases/prod/collections/docs":
+			w.Write([]byte(`{"id": "col-123", "name": "docs", "dimension": 3,
+				"configuration_json": {"hnsw": {"space": "cosine"}}}`))
+		case "/api/v2/tenants/acme/databases/prod/collections/col-123/count":
+			w.Write([]byte("2"))
+		case "/api/v2/tenants/acme/databases/prod/collections/col-123/query":
+			w.Write([]byte(`{"ids": [["a"]]}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+	col, err := client.Collection(ctx, "docs", "acme", "prod")
+	if err != nil {
+		t.Fatalf("Collection() error = %v", err)
+	}
+
+	if col.ID() != "col-123" || col.Name() != "docs" {
+		t.Errorf("Expected col-123/docs, got %s/%s", col.ID(), col.Name())
+	}
+	if dim, ok := col.Dimension(); !ok || dim != 3 {
+		t.Errorf("Expected dimension 3, got %d (%v)", dim, ok)
+	}
+	if col.Space() != SpaceCosine {
+		t.Errorf("Expected space cosine, got %s", col.Space())
+	}
+
+	if err := col.Add(ctx, AddEmbedding{IDs: []string{"a"}, Embeddings: [][]float64{{1, 2, 3}}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if count, err := col.Count(ctx); err != nil || count != 2 {
+		t.Fatalf("Count() = %d, %v", count, err)
+	}
+	if _, err := col.Query(ctx, QueryEmbedding{QueryEmbeddings: [][]float64{{1, 2, 3}}}); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	want := []string{
+		"GET /api/v2/tenants/acme/databases/prod/collections/docs",
+		"POST /api/v2/tenants/acme/databases/prod/collections/col-123/add",
+		"GET /api/v2/tenants/acme/databases/prod/collections/col-123/count",
+		"POST /api/v2/tenants/acme/databases/prod/collections/col-123/query",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("Expected %d requests, got %v", len(want), paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("Request %d: expected %s, got %s", i, want[i], paths[i])
+		}
+	}
+}
+
+func TestCollectionHandleDefaults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "col-123", "name": "docs"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	col, err := client.Collection(context.Background(), "docs", "", "")
+	if err != nil {
+		t.Fatalf("Collection() error = %v", err)
+	}
+	if _, ok := col.Dimension(); ok {
+		t.Error("Expected no dimension before the first add")
+	}
+	if col.Space() != SpaceL2 {
+		t.Errorf("Expected default space l2, got %s", col.Space())
+	}
+}
+
+func TestCollectionNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if _, err := client.Collection(context.Background(), "missing", "", ""); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestCollectionHandleDimensionValidation(t *testing.T) {
+	var writes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": "col-123", "name": "docs", "dimension": 3}`))
+			return
+		}
+		writes++
+		w.Write([]byte(`{"ids": [[]]}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(WithBaseURL(server.URL))
+	col, err := client.Collection(ctx, "docs", "", "")
+	if err != nil {
+		t.Fatalf("Collection() error = %v", err)
+	}
+
+	err = col.Add(ctx, AddEmbedding{
+		IDs:        []string{"a", "b"},
+		Embeddings: [][]float64{{1, 2, 3}, {1, 2}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "embedding 1 has dimension 2, collection expects 3") {
+		t.Errorf("Expected dimension error for row 1, got %v", err)
+	}
+
+	_, err = col.Query(ctx, QueryEmbedding{QueryEmbeddings: [][]float64{{1, 2, 3, 4}}})
+	if err == nil || !strings.Contains(err.Error(), "query embedding 0 has dimension 4") {
+		t.Errorf("Expected query dimension error, got %v", err)
+	}
+	if writes != 0 {
+		t.Errorf("Expected no requests to be sent, got %d", writes)
+	}
+
+	client = NewClient(WithBaseURL(server.URL), WithDimensionValidation(false))
+	col, err = client.Collection(ctx, "docs", "", "")
+	if err != nil {
+		t.Fatalf("Collection() error = %v", err)
+	}
+	if _, err := col.Query(ctx, QueryEmbedding{QueryEmbeddings: [][]float64{{1, 2, 3, 4}}}); err != nil {
+		t.Errorf("Expected validation to be skipped, got %v", err)
+	}
+	if writes != 1 {
+		t.Errorf("Expected the query to be sent, got %d requests", writes)
+	}
+}
+
+func TestCollectionHandleDimensionValidationAfterEmbedding(t *testing.T) {
+	var writes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": "col-123", "name": "docs", "dimension": 3}`))
+			return
+		}
+		writes++
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	ef := &fakeEmbeddingFunction{}
+	client := NewClient(WithBaseURL(server.URL), WithEmbeddingFunction(ef))
+	col, err := client.Collection(ctx, "docs", "", "")
+	if err != nil {
+		t.Fatalf("Collection() error = %v", err)
+	}
+
+	req := AddEmbedding{IDs: []string{"a"}, Documents: []string{"hello"}}
+	if err := col.Add(ctx, req); err == nil || !strings.Contains(err.Error(), "embedding 0 has dimension 1, collection expects 3") {
+		t.Errorf("Expected the embedded vector to fail the dimension check, got %v", err)
+	}
+	if err := col.Upsert(ctx, req); err == nil || !strings.Contains(err.Error(), "embedding 0 has dimension 1, collection expects 3") {
+		t.Errorf("Expected the embedded vector to fail the dimension check, got %v", err)
+	}
+	if writes != 0 {
+		t.Errorf("Expected no writes to be sent, got %d", writes)
+	}
+	if ef.calls != 2 {
+		t.Errorf("Expected each document to be embedded once per call, got %d calls", ef.calls)
+	}
+
+	if _, err := col.Query(ctx, QueryEmbedding{QueryTexts: []string{"hello"}}); err == nil || !strings.Contains(err.Error(), "query embedding 0 has dimension 1, collection expects 3") {
+		t.Errorf("Expected the embedded query to fail the dimension check, got %v", err)
+	}
+	if _, err := col.QueryByText(ctx, []string{"hello"}, QueryEmbedding{}); err == nil || !strings.Contains(err.Error(), "query embedding 0 has dimension 1, collection expects 3") {
+		t.Errorf("Expected the embedded query to fail the dimension check, got %v", err)
+	}
+	if writes != 0 || ef.calls != 4 {
+		t.Errorf("Expected queries to be embedded but not sent, got %d writes and %d calls", writes, ef.calls)
+	}
+}
+
+// modelEmbeddingFunction is a fakeEmbeddingFunction that reports a model
+type modelEmbeddingFunction struct {
+	fakeEmbeddingFunction
+	model string
+}
+
+func (f *modelEmbeddingFunction) Model() string {
+	return f.model
+}
+
+func TestCollectionHandleQueryByText(t *testing.T) {
+	var queries []QueryEmbedding
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/collections/docs"):
+			w.Write([]byte(`{"id": "col-docs", "name": "docs", "configuration_json": {"embedding_function":
+				{"type": "known", "name": "openai", "config": {"model_name": "text-embedding-3-small"}}}}`))
+		case strings.HasSuffix(r.URL.Path, "/collections/legacy"):
+			w.Write([]byte(`{"id": "col-legacy", "name": "legacy", "configuration_json": {"embedding_function": {"type": "legacy"}}}`))
+		case strings.HasSuffix(r.URL.Path, "/collections/custom"):
+			w.Write([]byte(`{"id": "col-custom", "name": "custom", "configuration_json": {"embedding_function": {"type": "unknown", "name": "my_ef"}}}`))
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			var req QueryEmbedding
+			json.NewDecoder(r.Body).Decode(&req)
+			queries = append(queries, req)
+			w.Write([]byte(`{"ids": [["a"]]}`))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	collection := func(client *Client, name string) *CollectionHandle {
+		t.Helper()
+		col, err := client.Collection(ctx, name, "", "")
+		if err != nil {
+			t.Fatalf("Collection(%s) error = %v", name, err)
+		}
+		return col
+	}
+
+	// Matching client model: embedded client-side
+	client := NewClient(WithBaseURL(server.URL),
+		WithEmbeddingFunction(&modelEmbeddingFunction{model: "text-embedding-3-small"}))
+	if _, err := collection(client, "docs").QueryByText(ctx, []string{"hello"}, QueryEmbedding{}); err != nil {
+		t.Fatalf("QueryByText() error = %v", err)
+	}
+	if len(queries) != 1 || len(queries[0].QueryEmbeddings) != 1 || queries[0].QueryTexts != nil {
+		t.Errorf("Expected a client-side embedded query, got %+v", queries)
+	}
+
+	// Mismatched client model: rejected before sending
+	client = NewClient(WithBaseURL(server.URL),
+		WithEmbeddingFunction(&modelEmbeddingFunction{model: "nomic-embed-text"}))
+	_, err := collection(client, "docs").QueryByText(ctx, []string{"hello"}, QueryEmbedding{})
+	if err == nil || !strings.Contains(err.Error(), `embeds with model "text-embedding-3-small", client embedding function uses "nomic-embed-text"`) {
+		t.Errorf("Expected model mismatch error, got %v", err)
+	}
+	if len(queries) != 1 {
+		t.Errorf("Expected the mismatched query not to be sent, got %d queries", len(queries))
+	}
+
+	// No client function: the server embeds the texts
+	client = NewClient(WithBaseURL(server.URL))
+	if _, err := collection(client, "docs").QueryByText(ctx, []string{"hello"}, QueryEmbedding{}); err != nil {
+		t.Fatalf("QueryByText() error = %v", err)
+	}
+	if len(queries) != 2 || !slices.Equal(queries[1].QueryTexts, []string{"hello"}) {
+		t.Errorf("Expected server-side query_texts, got %+v", queries)
+	}
+
+	// Neither side can embed: legacy and unknown functions run client-side
+	for _, name := range []string{"legacy", "custom"} {
+		if _, err := collection(client, name).QueryByText(ctx, []string{"hello"}, QueryEmbedding{}); err == nil || !strings.Contains(err.Error(), "no embedding function") {
+			t.Errorf("%s: expected missing embedding function error, got %v", name, err)
+		}
+	}
+	if len(queries) != 2 {
+		t.Errorf("Expected queries nobody can embed not to be sent, got %d queries", len(queries))
+	}
+}
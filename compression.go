@@ -0,0 +1,70 @@
+package chromaclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultCompressionMinSize is the request body size, in bytes, at which
+// WithCompression starts compressing when given a non-positive size
+const DefaultCompressionMinSize = 1024
+
+// WithCompression gzips request bodies of at least minSize bytes and sends
+// them with Content-Encoding: gzip. Smaller bodies are sent as is, since
+// compressing them costs more CPU than it saves on the wire. A minSize <= 0
+// uses DefaultCompressionMinSize. The server, or a proxy in front of it,
+// must accept gzip-encoded request bodies.
+func WithCompression(minSize int) ClientOption {
+	return func(c *Client) {
+		if minSize <= 0 {
+			minSize = DefaultCompressionMinSize
+		}
+		c.compressMinSize = minSize
+	}
+}
+
+// gzipBytes returns data gzip-compressed
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodedBody returns the response body, gunzipping it if the server sent
+// Content-Encoding: gzip and the transport has not already decoded it. This
+// happens behind compressing proxies or with a transport that has
+// DisableCompression set. Closing it closes the response body too.
+func decodedBody(resp *http.Response) (io.ReadCloser, error) {
+	if resp.Uncompressed || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp.Body, nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &gzipBody{Reader: zr, body: resp.Body}, nil
+}
+
+// gzipBody is a gunzipped response body that closes both the decompressor
+// and the underlying body
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	err := b.Reader.Close()
+	if bodyErr := b.body.Close(); err == nil {
+		err = bodyErr
+	}
+	return err
+}
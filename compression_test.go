@@ -0,0 +1,174 @@
+package chromaclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRequestCompression(t *testing.T) {
+	tests := []struct {
+		name     string
+		docs     int
+		wantGzip bool
+	}{
+		{"large body", 100, true},
+		{"small body", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got AddEmbedding
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gzipped := r.Header.Get("Content-Encoding") == "gzip"
+				if gzipped != tt.wantGzip {
+					t.Errorf("Expected gzip %v, got Content-Encoding %q", tt.wantGzip, r.Header.Get("Content-Encoding"))
+				}
+				if r.ContentLength <= 0 {
+					t.Errorf("Expected Content-Length to be set, got %d", r.ContentLength)
+				}
+
+				body := io.Reader(r.Body)
+				if gzipped {
+					zr, err := gzip.NewReader(r.Body)
+					if err != nil {
+						t.Fatalf("Failed to open gzip body: %v", err)
+					}
+					body = zr
+				}
+				if err := json.NewDecoder(body).Decode(&got); err != nil {
+					t.Fatalf("Failed to decode request body: %v", err)
+				}
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer server.Close()
+
+			req := AddEmbedding{}
+			for i := 0; i < tt.docs; i++ {
+				req.IDs = append(req.IDs, "id"+strconv.Itoa(i))
+				req.Documents = append(req.Documents, strings.Repeat("chroma ", 5))
+			}
+
+			client := NewClient(WithBaseURL(server.URL), WithCompression(0))
+			if err := client.Add(context.Background(), "col-123", req, "", ""); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if len(got.IDs) != tt.docs || got.Documents[0] != req.Documents[0] {
+				t.Errorf("Expected server to round-trip %d records, got %+v", tt.docs, got)
+			}
+		})
+	}
+}
+
+func TestGzipResponseDecompression(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(`{"ids": ["a", "b"], "documents": ["doc a", "doc b"]}`))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		httpClient *http.Client
+	}{
+		{"default transport", &http.Client{}},
+		{"compression disabled", &http.Client{Transport: &http.Transport{DisableCompression: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(WithBaseURL(server.URL), WithHTTPClient(tt.httpClient))
+			result, err := client.Get(context.Background(), "col-123", GetEmbedding{}, "", "")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if len(result.IDs) != 2 || result.Documents[1] != "doc b" {
+				t.Errorf("Unexpected result %+v", result)
+			}
+		})
+	}
+}
+
+func TestGzipErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusBadRequest)
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte("invalid where clause"))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithHTTPClient(&http.Client{Transport: &http.Transport{DisableCompression: true}}),
+	)
+	_, err := client.Get(context.Background(), "col-123", GetEmbedding{}, "", "")
+	if err == nil || err.Error() != "invalid where clause" {
+		t.Errorf("Expected decompressed error message, got %v", err)
+	}
+}
+
+// closeTracker is a response body that records whether it was closed
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeTracker) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDecodedBodyClosesResponseBody(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(`"1.0.0"`))
+	zw.Close()
+
+	raw := &closeTracker{Reader: &buf}
+	resp := &http.Response{Header: http.Header{"Content-Encoding": {"gzip"}}, Body: raw}
+	body, err := decodedBody(resp)
+	if err != nil {
+		t.Fatalf("decodedBody() error = %v", err)
+	}
+	if data, err := io.ReadAll(body); err != nil || string(data) != `"1.0.0"` {
+		t.Errorf("Expected the decompressed body, got %q, %v", data, err)
+	}
+	if err := body.Close(); err != nil || !raw.closed {
+		t.Errorf("Expected Close to close the response body, got closed %v, %v", raw.closed, err)
+	}
+}
+
+func TestGzipResponseChecksumError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write([]byte(`"1.0.0"`))
+		zw.Close()
+		data := buf.Bytes()
+		data[len(data)-8] ^= 0xff // corrupt the CRC-32 in the trailer
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithHTTPClient(&http.Client{Transport: &http.Transport{DisableCompression: true}}),
+	)
+	if _, err := client.Version(context.Background()); !errors.Is(err, gzip.ErrChecksum) {
+		t.Errorf("Expected a checksum error, got %v", err)
+	}
+}
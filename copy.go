@@ -0,0 +1,63 @@
+package chromaclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// CopyOption configures CopyCollection
+type CopyOption func(*copyConfig)
+
+type copyConfig struct {
+	progress func(copied int)
+}
+
+// WithCopyProgress makes CopyCollection call fn after each batch with the
+// number of records copied so far
+func WithCopyProgress(fn func(copied int)) CopyOption {
+	return func(c *copyConfig) {
+		c.progress = fn
+	}
+}
+
+// CopyCollection upserts every record of the source collection into the
+// destination, batchSize records at a time. IDs, embeddings, documents,
+// metadatas and URIs are preserved. If batchSize is 0 the server's
+// max_batch_size from PreFlightChecks is used. Both collections live in the
+// given tenant and database.
+func (c *Client) CopyCollection(ctx context.Context, srcID, dstID string, batchSize int, tenant, database string, opts ...CopyOption) error {
+	if srcID == dstID {
+		return fmt.Errorf("source and destination collections must differ, got %s for both", srcID)
+	}
+	var cfg copyConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	batchSize, err := c.resolveBatchSize(ctx, batchSize)
+	if err != nil {
+		return err
+	}
+
+	req := GetEmbedding{
+		Include: Includes(IncludeEmbeddings, IncludeDocuments, IncludeMetadatas, IncludeUris),
+	}
+	copied, pages := 0, 0
+	return c.GetAllFunc(ctx, srcID, req, batchSize, func(page *GetResult) error {
+		pages++
+		err := c.Upsert(batchContext(ctx, pages-1), dstID, AddEmbedding{
+			IDs:        page.IDs,
+			Embeddings: page.Embeddings,
+			Documents:  page.Documents,
+			Metadatas:  page.Metadatas,
+			Uris:       page.Uris,
+		}, tenant, database)
+		if err != nil {
+			return fmt.Errorf("failed to copy records at offset %d: %w", copied, err)
+		}
+		copied += len(page.IDs)
+		if cfg.progress != nil {
+			cfg.progress(copied)
+		}
+		return nil
+	}, tenant, database)
+}
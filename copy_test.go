@@ -0,0 +1,81 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCopyCollection(t *testing.T) {
+	const total = 5
+	var upserted AddEmbedding
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/tenants/default_tenant/databases/default_database/collections/src/get":
+			var req GetEmbedding
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("Failed to decode request body: %v", err)
+			}
+			if len(req.Include) != 4 {
+				t.Errorf("Expected embeddings, documents, metadatas and uris to be included, got %v", req.Include)
+			}
+			var result GetResult
+			for i := *req.Offset; i < min(*req.Offset+*req.Limit, total); i++ {
+				result.IDs = append(result.IDs, fmt.Sprintf("id%d", i))
+				result.Embeddings = append(result.Embeddings, []float64{float64(i)})
+				result.Documents = append(result.Documents, fmt.Sprintf("doc%d", i))
+				result.Metadatas = append(result.Metadatas, map[string]interface{}{"n": float64(i)})
+				result.Uris = append(result.Uris, fmt.Sprintf("uri%d", i))
+			}
+			json.NewEncoder(w).Encode(result)
+		case "/api/v2/tenants/default_tenant/databases/default_database/collections/dst/upsert":
+			var req AddEmbedding
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("Failed to decode request body: %v", err)
+			}
+			upserted.IDs = append(upserted.IDs, req.IDs...)
+			upserted.Embeddings = append(upserted.Embeddings, req.Embeddings...)
+			upserted.Documents = append(upserted.Documents, req.Documents...)
+			upserted.Metadatas = append(upserted.Metadatas, req.Metadatas...)
+			upserted.Uris = append(upserted.Uris, req.Uris...)
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	var progress []int
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.CopyCollection(context.Background(), "src", "dst", 2, "", "", WithCopyProgress(func(copied int) {
+		progress = append(progress, copied)
+	}))
+	if err != nil {
+		t.Fatalf("CopyCollection() error = %v", err)
+	}
+
+	if fmt.Sprint(progress) != "[2 4 5]" {
+		t.Errorf("Expected progress [2 4 5], got %v", progress)
+	}
+	if len(upserted.IDs) != total || upserted.IDs[4] != "id4" {
+		t.Errorf("Expected ids id0..id4, got %v", upserted.IDs)
+	}
+	if len(upserted.Embeddings) != total || upserted.Embeddings[4][0] != 4 {
+		t.Errorf("Expected embeddings to be copied, got %v", upserted.Embeddings)
+	}
+	if len(upserted.Documents) != total || len(upserted.Metadatas) != total || len(upserted.Uris) != total {
+		t.Errorf("Expected documents, metadatas and uris to be copied, got %v", upserted)
+	}
+}
+
+func TestCopyCollectionSameID(t *testing.T) {
+	client := NewClient(WithBaseURL("http://127.0.0.1:0"))
+	err := client.CopyCollection(context.Background(), "col-123", "col-123", 10, "", "")
+	if err == nil || !strings.Contains(err.Error(), "must differ") {
+		t.Errorf("Expected error for identical collections, got %v", err)
+	}
+}
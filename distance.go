@@ -0,0 +1,96 @@
+package chromaclient
+
+import "math"
+
+// The functions in this file return NaN when the two vectors have different
+// lengths, so a mismatch can't be mistaken for a real score.
+
+// CosineSimilarity returns the cosine of the angle between a and b, in
+// [-1, 1]. It returns 0 if either vector has zero magnitude.
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return math.NaN()
+	}
+
+	var dot, magA, magB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		magA += a[i] * a[i]
+		magB += b[i] * b[i]
+	}
+	if magA == 0 || magB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(magA) * math.Sqrt(magB))
+}
+
+// L2Distance returns the squared Euclidean distance between a and b. This is
+// the value ChromaDB reports for SpaceL2 collections; take math.Sqrt of it for
+// the plain Euclidean distance.
+func L2Distance(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return math.NaN()
+	}
+
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// InnerProduct returns the dot product of a and b
+func InnerProduct(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return math.NaN()
+	}
+
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// NormalizeVector returns a copy of v scaled to unit length. A zero vector is
+// returned unchanged.
+func NormalizeVector(v []float64) []float64 {
+	var magnitude float64
+	for _, x := range v {
+		magnitude += x * x
+	}
+	magnitude = math.Sqrt(magnitude)
+
+	out := make([]float64, len(v))
+	copy(out, v)
+	if magnitude == 0 {
+		return out
+	}
+	for i := range out {
+		out[i] /= magnitude
+	}
+	return out
+}
+
+// Distance returns the distance between a and b as ChromaDB computes it for a
+// collection using space s, so client-side scores line up with
+// QueryResult.Distances. Smaller is closer in every space:
+//
+//   - SpaceL2: squared Euclidean distance
+//   - SpaceCosine: 1 - cosine similarity
+//   - SpaceIP: 1 - inner product
+//
+// It returns NaN for an unknown space.
+func (s Space) Distance(a, b []float64) float64 {
+	switch s {
+	case SpaceL2:
+		return L2Distance(a, b)
+	case SpaceCosine:
+		return 1 - CosineSimilarity(a, b)
+	case SpaceIP:
+		return 1 - InnerProduct(a, b)
+	default:
+		return math.NaN()
+	}
+}
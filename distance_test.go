@@ -0,0 +1,85 @@
+package chromaclient
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	if got := CosineSimilarity([]float64{1, 0}, []float64{1, 0}); !almostEqual(got, 1) {
+		t.Errorf("Expected identical vectors to have similarity 1, got %f", got)
+	}
+	if got := CosineSimilarity([]float64{1, 0}, []float64{0, 1}); !almostEqual(got, 0) {
+		t.Errorf("Expected orthogonal vectors to have similarity 0, got %f", got)
+	}
+	if got := CosineSimilarity([]float64{0, 0}, []float64{1, 1}); got != 0 {
+		t.Errorf("Expected zero vector to have similarity 0, got %f", got)
+	}
+}
+
+func TestL2Distance(t *testing.T) {
+	if got := L2Distance([]float64{0, 0}, []float64{3, 4}); !almostEqual(got, 25) {
+		t.Errorf("Expected squared distance 25, got %f", got)
+	}
+}
+
+func TestInnerProduct(t *testing.T) {
+	if got := InnerProduct([]float64{1, 2, 3}, []float64{4, 5, 6}); !almostEqual(got, 32) {
+		t.Errorf("Expected inner product 32, got %f", got)
+	}
+}
+
+func TestNormalizeVector(t *testing.T) {
+	v := []float64{3, 4}
+	got := NormalizeVector(v)
+	if !almostEqual(got[0], 0.6) || !almostEqual(got[1], 0.8) {
+		t.Errorf("Expected [0.6 0.8], got %v", got)
+	}
+	if v[0] != 3 {
+		t.Error("Expected NormalizeVector not to modify its input")
+	}
+
+	zero := NormalizeVector([]float64{0, 0})
+	if zero[0] != 0 || zero[1] != 0 {
+		t.Errorf("Expected zero vector to stay zero, got %v", zero)
+	}
+}
+
+func TestDistanceMismatchedLengths(t *testing.T) {
+	a, b := []float64{1, 2}, []float64{1}
+	for name, got := range map[string]float64{
+		"CosineSimilarity": CosineSimilarity(a, b),
+		"L2Distance":       L2Distance(a, b),
+		"InnerProduct":     InnerProduct(a, b),
+	} {
+		if !math.IsNaN(got) {
+			t.Errorf("Expected %s to return NaN for mismatched lengths, got %f", name, got)
+		}
+	}
+}
+
+func TestSpaceDistance(t *testing.T) {
+	a, b := []float64{1, 0}, []float64{0, 1}
+
+	tests := []struct {
+		space Space
+		want  float64
+	}{
+		{SpaceL2, 2},
+		{SpaceCosine, 1},
+		{SpaceIP, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.space.Distance(a, b); !almostEqual(got, tt.want) {
+			t.Errorf("Expected %s distance %f, got %f", tt.space, tt.want, got)
+		}
+	}
+
+	if got := Space("cosign").Distance(a, b); !math.IsNaN(got) {
+		t.Errorf("Expected unknown space to return NaN, got %f", got)
+	}
+}
@@ -0,0 +1,126 @@
+package chromaclient
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// EmbeddingCacheMetrics is implemented by a Metrics value that also wants
+// embedding cache statistics. It is called once per embedding call with the
+// number of texts served from the cache and the number sent to the provider.
+type EmbeddingCacheMetrics interface {
+	ObserveEmbeddingCache(hits, misses int)
+}
+
+// WithEmbeddingCache keeps the embeddings of up to size recently used texts
+// in memory, so repeated documents and query texts are embedded only once.
+// Entries are keyed by text and by the embedding function's model, if it has
+// a Model() string method.
+func WithEmbeddingCache(size int) ClientOption {
+	return func(c *Client) {
+		if size <= 0 {
+			c.addErr(fmt.Errorf("invalid embedding cache: size must be positive, got %d", size))
+			return
+		}
+		c.embeddingCache = newEmbeddingCache(size)
+	}
+}
+
+// embeddingCache is a concurrency-safe LRU cache of embeddings. It stores
+// and hands out copies, so callers that modify a returned vector cannot
+// corrupt the cache.
+type embeddingCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type embeddingCacheEntry struct {
+	key       string
+	embedding []float64
+}
+
+func newEmbeddingCache(size int) *embeddingCache {
+	return &embeddingCache{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *embeddingCache) get(key string) ([]float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return slices.Clone(elem.Value.(*embeddingCacheEntry).embedding), true
+}
+
+func (c *embeddingCache) put(key string, embedding []float64) {
+	embedding = slices.Clone(embedding)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*embeddingCacheEntry).embedding = embedding
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&embeddingCacheEntry{key: key, embedding: embedding})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*embeddingCacheEntry).key)
+	}
+}
+
+// embedCached embeds texts with the client's embedding function, serving
+// what it can from the embedding cache and sending only the rest. A text
+// repeated within texts is sent once.
+func (c *Client) embedCached(ctx context.Context, texts []string) ([][]float64, error) {
+	if c.embeddingCache == nil {
+		return embed(ctx, c.embeddingFunction, texts)
+	}
+
+	model, _ := embeddingModel(c.embeddingFunction)
+	key := func(text string) string { return model + "\x00" + text }
+
+	embeddings := make([][]float64, len(texts))
+	var missing []string
+	missIdx := make(map[string][]int)
+	for i, text := range texts {
+		if e, ok := c.embeddingCache.get(key(text)); ok {
+			embeddings[i] = e
+			continue
+		}
+		if _, ok := missIdx[text]; !ok {
+			missing = append(missing, text)
+		}
+		missIdx[text] = append(missIdx[text], i)
+	}
+	if m, ok := c.metrics.(EmbeddingCacheMetrics); ok {
+		m.ObserveEmbeddingCache(len(texts)-len(missing), len(missing))
+	}
+	if len(missing) == 0 {
+		return embeddings, nil
+	}
+
+	computed, err := embed(ctx, c.embeddingFunction, missing)
+	if err != nil {
+		return nil, err
+	}
+	for j, text := range missing {
+		c.embeddingCache.put(key(text), computed[j])
+		for n, i := range missIdx[text] {
+			// every repeat gets its own copy, like a cache hit would
+			if n == 0 {
+				embeddings[i] = computed[j]
+			} else {
+				embeddings[i] = slices.Clone(computed[j])
+			}
+		}
+	}
+	return embeddings, nil
+}
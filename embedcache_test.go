@@ -0,0 +1,121 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+// cacheMetrics records embedding cache observations
+type cacheMetrics struct {
+	hits, misses int
+}
+
+func (m *cacheMetrics) ObserveRequest(string, int, time.Duration) {}
+
+func (m *cacheMetrics) ObserveEmbeddingCache(hits, misses int) {
+	m.hits += hits
+	m.misses += misses
+}
+
+func TestEmbeddingCache(t *testing.T) {
+	var sent [][]float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AddEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		sent = req.Embeddings
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	ef := &fakeEmbeddingFunction{}
+	metrics := &cacheMetrics{}
+	client := NewClient(WithBaseURL(server.URL), WithEmbeddingFunction(ef), WithEmbeddingCache(2), WithMetrics(metrics))
+	ctx := context.Background()
+	add := func(docs ...string) {
+		t.Helper()
+		if err := client.Add(ctx, "col-123", AddEmbedding{IDs: docs, Documents: docs}, "", ""); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	add("a", "bb")
+	add("bb", "a")
+	if ef.calls != 1 {
+		t.Errorf("Expected cached texts not to be embedded again, got %d calls", ef.calls)
+	}
+	if sent[0][0] != 2 || sent[1][0] != 1 {
+		t.Errorf("Expected cached embeddings in request order, got %v", sent)
+	}
+
+	// "ccc" evicts the least recently used entry, "bb"
+	add("ccc", "a")
+	add("bb")
+	if ef.calls != 3 {
+		t.Errorf("Expected an evicted text to be embedded again, got %d calls", ef.calls)
+	}
+	if metrics.hits != 3 || metrics.misses != 4 {
+		t.Errorf("Expected 3 hits and 4 misses, got %d and %d", metrics.hits, metrics.misses)
+	}
+}
+
+func TestWithEmbeddingCacheInvalid(t *testing.T) {
+	if err := NewClient(WithEmbeddingCache(0)).Err(); err == nil {
+		t.Error("Expected error for zero cache size")
+	}
+}
+
+// recordingEmbeddingFunction is a fakeEmbeddingFunction that records the
+// texts of each call
+type recordingEmbeddingFunction struct {
+	fakeEmbeddingFunction
+	texts [][]string
+}
+
+func (f *recordingEmbeddingFunction) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	f.texts = append(f.texts, texts)
+	return f.fakeEmbeddingFunction.Embed(ctx, texts)
+}
+
+func TestEmbeddingCacheDedupAndCopies(t *testing.T) {
+	ef := &recordingEmbeddingFunction{}
+	client := NewClient(WithEmbeddingFunction(ef), WithEmbeddingCache(4))
+	ctx := context.Background()
+
+	got, err := client.embedCached(ctx, []string{"x", "yy", "x"})
+	if err != nil {
+		t.Fatalf("embedCached() error = %v", err)
+	}
+	if len(ef.texts) != 1 || !slices.Equal(ef.texts[0], []string{"x", "yy"}) {
+		t.Errorf("Expected repeated texts to be embedded once, got %v", ef.texts)
+	}
+	if got[0][0] != 1 || got[1][0] != 2 || got[2][0] != 1 {
+		t.Errorf("Expected embeddings in request order, got %v", got)
+	}
+
+	// modifying returned vectors must not reach the cache or other results
+	got[0][0], got[1][0] = 99, 99
+	if got[2][0] != 1 {
+		t.Errorf("Expected repeated texts to get separate vectors, got %v", got)
+	}
+	again, err := client.embedCached(ctx, []string{"x", "yy"})
+	if err != nil {
+		t.Fatalf("embedCached() error = %v", err)
+	}
+	if again[0][0] != 1 || again[1][0] != 2 {
+		t.Errorf("Expected the cache to be unaffected by callers, got %v", again)
+	}
+	again[0][0] = 99
+	if cached, _ := client.embedCached(ctx, []string{"x"}); cached[0][0] != 1 {
+		t.Errorf("Expected cache hits to be copies, got %v", cached)
+	}
+	if len(ef.texts) != 1 {
+		t.Errorf("Expected cached texts not to be embedded again, got %v", ef.texts)
+	}
+}
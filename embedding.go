@@ -0,0 +1,73 @@
+package chromaclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// EmbeddingFunction turns texts into embedding vectors. Implementations must
+// return exactly one embedding per input text, in the same order.
+type EmbeddingFunction interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// WithEmbeddingFunction sets an embedding function used to embed documents
+// and query texts client-side when a request does not carry embeddings.
+func WithEmbeddingFunction(ef EmbeddingFunction) ClientOption {
+	return func(c *Client) {
+		c.embeddingFunction = ef
+	}
+}
+
+// embeddingModel returns the model of ef, if it reports one with a
+// Model() string method
+func embeddingModel(ef EmbeddingFunction) (string, bool) {
+	m, ok := ef.(interface{ Model() string })
+	if !ok {
+		return "", false
+	}
+	return m.Model(), true
+}
+
+// embed calls ef and checks that it returned one embedding per text
+func embed(ctx context.Context, ef EmbeddingFunction, texts []string) ([][]float64, error) {
+	embeddings, err := ef.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("embedding function returned %d embeddings for %d texts", len(embeddings), len(texts))
+	}
+	return embeddings, nil
+}
+
+// embedDocuments fills in req.Embeddings from req.Documents when the client
+// has an embedding function and the caller did not supply embeddings
+func (c *Client) embedDocuments(ctx context.Context, req AddEmbedding) (AddEmbedding, error) {
+	if c.embeddingFunction == nil || len(req.Embeddings) > 0 || len(req.Documents) == 0 {
+		return req, nil
+	}
+
+	embeddings, err := c.embedCached(ctx, req.Documents)
+	if err != nil {
+		return req, err
+	}
+	req.Embeddings = embeddings
+	return req, nil
+}
+
+// embedQueryTexts replaces req.QueryTexts with client-side embeddings when the
+// client has an embedding function
+func (c *Client) embedQueryTexts(ctx context.Context, req QueryEmbedding) (QueryEmbedding, error) {
+	if c.embeddingFunction == nil || len(req.QueryEmbeddings) > 0 || len(req.QueryTexts) == 0 {
+		return req, nil
+	}
+
+	embeddings, err := c.embedCached(ctx, req.QueryTexts)
+	if err != nil {
+		return req, err
+	}
+	req.QueryEmbeddings = embeddings
+	req.QueryTexts = nil
+	return req, nil
+}
@@ -0,0 +1,160 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeEmbeddingFunction embeds each text as a single-element vector of its length
+type fakeEmbeddingFunction struct {
+	calls int
+	err   error
+}
+
+func (f *fakeEmbeddingFunction) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	embeddings := make([][]float64, len(texts))
+	for i, text := range texts {
+		embeddings[i] = []float64{float64(len(text))}
+	}
+	return embeddings, nil
+}
+
+func TestAddWithEmbeddingFunction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AddEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if len(req.Embeddings) != 2 {
+			t.Fatalf("Expected 2 embeddings, got %d", len(req.Embeddings))
+		}
+		if req.Embeddings[1][0] != 5 {
+			t.Errorf("Expected embedding of %q to be [5], got %v", req.Documents[1], req.Embeddings[1])
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	ef := &fakeEmbeddingFunction{}
+	client := NewClient(WithBaseURL(server.URL), WithEmbeddingFunction(ef))
+	err := client.Add(context.Background(), "col-123", AddEmbedding{
+		IDs:       []string{"id1", "id2"},
+		Documents: []string{"doc", "hello"},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if ef.calls != 1 {
+		t.Errorf("Expected embedding function to be called once, got %d", ef.calls)
+	}
+}
+
+func TestAddWithEmbeddingFunctionKeepsProvidedEmbeddings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	ef := &fakeEmbeddingFunction{}
+	client := NewClient(WithBaseURL(server.URL), WithEmbeddingFunction(ef))
+	err := client.Add(context.Background(), "col-123", AddEmbedding{
+		IDs:        []string{"id1"},
+		Documents:  []string{"doc"},
+		Embeddings: [][]float64{{0.1}},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if ef.calls != 0 {
+		t.Errorf("Expected embedding function not to be called, got %d calls", ef.calls)
+	}
+}
+
+func TestQueryWithEmbeddingFunction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if _, ok := req["query_texts"]; ok {
+			t.Errorf("Expected query_texts to be replaced by embeddings, got %s", req["query_texts"])
+		}
+		if got := string(req["query_embeddings"]); got != "[[4]]" {
+			t.Errorf("Expected query_embeddings [[4]], got %s", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(QueryResult{IDs: [][]string{{"id1"}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithEmbeddingFunction(&fakeEmbeddingFunction{}))
+	_, err := client.QueryByText(context.Background(), "col-123", []string{"text"}, QueryEmbedding{}, "", "")
+	if err != nil {
+		t.Fatalf("QueryByText() error = %v", err)
+	}
+}
+
+func TestEmbeddingFunctionError(t *testing.T) {
+	client := NewClient(
+		WithBaseURL("http://127.0.0.1:0"),
+		WithEmbeddingFunction(&fakeEmbeddingFunction{err: errors.New("quota exceeded")}),
+	)
+	err := client.Add(context.Background(), "col-123", AddEmbedding{
+		IDs:       []string{"id1"},
+		Documents: []string{"doc"},
+	}, "", "")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("Expected embedding error to be wrapped, got %v", err)
+	}
+}
+
+func TestUpsertDocuments(t *testing.T) {
+	var reqs []AddEmbedding
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/collections/col-123/upsert") {
+			t.Errorf("Expected upsert path, got %s", r.URL.Path)
+		}
+		var req AddEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		reqs = append(reqs, req)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	docs := map[string]string{"c": "ccc", "a": "a", "b": "bb"}
+	ef := &fakeEmbeddingFunction{}
+	client := NewClient(WithBaseURL(server.URL))
+	if err := client.UpsertDocuments(context.Background(), "col-123", docs, ef, "", ""); err != nil {
+		t.Fatalf("UpsertDocuments() error = %v", err)
+	}
+	if err := client.UpsertDocuments(context.Background(), "col-123", docs, nil, "", ""); err != nil {
+		t.Fatalf("UpsertDocuments() error = %v", err)
+	}
+
+	first := reqs[0]
+	if strings.Join(first.IDs, ",") != "a,b,c" || strings.Join(first.Documents, ",") != "a,bb,ccc" {
+		t.Errorf("Expected records sorted by ID, got %v %v", first.IDs, first.Documents)
+	}
+	if ef.calls != 1 || len(first.Embeddings) != 3 || first.Embeddings[2][0] != 3 {
+		t.Errorf("Expected the given function to embed the documents, got %v", first.Embeddings)
+	}
+	if len(reqs[1].Embeddings) != 0 {
+		t.Errorf("Expected the server to embed without a function, got %v", reqs[1].Embeddings)
+	}
+}
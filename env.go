@@ -0,0 +1,99 @@
+package chromaclient
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv
+const (
+	EnvURL       = "CHROMA_URL"
+	EnvHost      = "CHROMA_HOST"
+	EnvTenant    = "CHROMA_TENANT"
+	EnvDatabase  = "CHROMA_DATABASE"
+	EnvAuthToken = "CHROMA_AUTH_TOKEN"
+	EnvTimeout   = "CHROMA_TIMEOUT"
+)
+
+// NewClientFromEnv creates a client configured from CHROMA_* environment
+// variables. CHROMA_URL is a full base URL; CHROMA_HOST is a host or host:port
+// used when CHROMA_URL is unset, defaulting to http and port 8000.
+// CHROMA_TIMEOUT is a Go duration ("45s") or a number of seconds. Unset
+// variables keep the NewClient defaults. opts are applied after the
+// environment, so they take precedence.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	var envOpts []ClientOption
+
+	if baseURL, err := baseURLFromEnv(); err != nil {
+		return nil, err
+	} else if baseURL != "" {
+		envOpts = append(envOpts, WithBaseURL(baseURL))
+	}
+	if tenant := os.Getenv(EnvTenant); tenant != "" {
+		envOpts = append(envOpts, WithTenant(tenant))
+	}
+	if database := os.Getenv(EnvDatabase); database != "" {
+		envOpts = append(envOpts, WithDatabase(database))
+	}
+	if token := os.Getenv(EnvAuthToken); token != "" {
+		envOpts = append(envOpts, WithAuthToken(token))
+	}
+	if v := os.Getenv(EnvTimeout); v != "" {
+		timeout, err := parseTimeout(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvTimeout, err)
+		}
+		envOpts = append(envOpts, WithTimeout(timeout))
+	}
+
+	c := NewClient(append(envOpts, opts...)...)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c, nil
+}
+
+// baseURLFromEnv returns the base URL from CHROMA_URL or CHROMA_HOST, or ""
+// if neither is set
+func baseURLFromEnv() (string, error) {
+	if v := os.Getenv(EnvURL); v != "" {
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", fmt.Errorf("invalid %s %q: must be an http or https URL", EnvURL, v)
+		}
+		return v, nil
+	}
+
+	host := os.Getenv(EnvHost)
+	if host == "" {
+		return "", nil
+	}
+	if strings.Contains(host, "://") {
+		return "", fmt.Errorf("invalid %s %q: use %s for a full URL", EnvHost, host, EnvURL)
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "8000")
+	}
+	return "http://" + host, nil
+}
+
+// parseTimeout parses a Go duration or a plain number of seconds
+func parseTimeout(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		seconds, serr := strconv.ParseFloat(v, 64)
+		if serr != nil {
+			return 0, fmt.Errorf("%q is not a duration or number of seconds", v)
+		}
+		d = time.Duration(seconds * float64(time.Second))
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%q must be positive", v)
+	}
+	return d, nil
+}
@@ -0,0 +1,108 @@
+package chromaclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/acme/databases/prod/collections_count" {
+			t.Errorf("Expected env tenant and database in path, got %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Expected bearer token, got %q", got)
+		}
+		w.Write([]byte("3"))
+	}))
+	defer server.Close()
+
+	t.Setenv(EnvURL, server.URL)
+	t.Setenv(EnvTenant, "acme")
+	t.Setenv(EnvDatabase, "prod")
+	t.Setenv(EnvAuthToken, "secret")
+	t.Setenv(EnvTimeout, "45s")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("NewClientFromEnv() error = %v", err)
+	}
+	if client.timeout != 45*time.Second {
+		t.Errorf("Expected timeout 45s, got %v", client.timeout)
+	}
+	if _, err := client.CountCollections(context.Background(), "", ""); err != nil {
+		t.Fatalf("CountCollections() error = %v", err)
+	}
+}
+
+func TestNewClientFromEnvDefaults(t *testing.T) {
+	for _, key := range []string{EnvURL, EnvHost, EnvTenant, EnvDatabase, EnvAuthToken, EnvTimeout} {
+		t.Setenv(key, "")
+	}
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("NewClientFromEnv() error = %v", err)
+	}
+	if client.baseURL != "http://localhost:8000" || client.tenant != DefaultTenant || client.database != DefaultDatabase {
+		t.Errorf("Expected defaults, got %s %s %s", client.baseURL, client.tenant, client.database)
+	}
+}
+
+func TestNewClientFromEnvHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"chroma", "http://chroma:8000"},
+		{"chroma:9000", "http://chroma:9000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			t.Setenv(EnvURL, "")
+			t.Setenv(EnvHost, tt.host)
+
+			client, err := NewClientFromEnv()
+			if err != nil {
+				t.Fatalf("NewClientFromEnv() error = %v", err)
+			}
+			if client.baseURL != tt.want {
+				t.Errorf("Expected base URL %s, got %s", tt.want, client.baseURL)
+			}
+		})
+	}
+}
+
+func TestNewClientFromEnvMalformed(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{EnvTimeout, "soon"},
+		{EnvTimeout, "-5"},
+		{EnvURL, "localhost:8000"},
+		{EnvHost, "http://chroma"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			t.Setenv(EnvURL, "")
+			t.Setenv(EnvHost, "")
+			t.Setenv(EnvTimeout, "")
+			t.Setenv(tt.key, tt.value)
+
+			_, err := NewClientFromEnv()
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("Expected error to name %s, got %v", tt.key, err)
+			}
+		})
+	}
+}
@@ -110,14 +110,10 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to get documents: %v", err)
 	}
-	for i, id := range getResult.IDs {
-		fmt.Printf("ID: %s\n", id)
-		if i < len(getResult.Documents) {
-			fmt.Printf("  Document: %s\n", getResult.Documents[i])
-		}
-		if i < len(getResult.Metadatas) {
-			fmt.Printf("  Metadata: %v\n", getResult.Metadatas[i])
-		}
+	for _, row := range getResult.Rows() {
+		fmt.Printf("ID: %s\n", row.ID)
+		fmt.Printf("  Document: %s\n", row.Document)
+		fmt.Printf("  Metadata: %v\n", row.Metadata)
 	}
 
 	// Update a document
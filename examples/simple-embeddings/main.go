@@ -5,7 +5,6 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"log"
-	"math"
 	"strings"
 
 	chromaclient "github.com/kevensen/go-chroma-client"
@@ -59,46 +58,7 @@ func (g *SimpleEmbeddingGenerator) GenerateEmbedding(text string) []float64 {
 	}
 
 	// Normalize the vector
-	return normalizeVector(embedding)
-}
-
-// normalizeVector normalizes a vector to unit length
-func normalizeVector(vec []float64) []float64 {
-	var magnitude float64
-	for _, v := range vec {
-		magnitude += v * v
-	}
-	magnitude = math.Sqrt(magnitude)
-
-	if magnitude > 0 {
-		normalized := make([]float64, len(vec))
-		for i, v := range vec {
-			normalized[i] = v / magnitude
-		}
-		return normalized
-	}
-
-	return vec
-}
-
-// cosineSimilarity calculates cosine similarity between two vectors (for testing)
-func cosineSimilarity(a, b []float64) float64 {
-	if len(a) != len(b) {
-		return 0
-	}
-
-	var dotProduct, magA, magB float64
-	for i := range a {
-		dotProduct += a[i] * b[i]
-		magA += a[i] * a[i]
-		magB += b[i] * b[i]
-	}
-
-	if magA == 0 || magB == 0 {
-		return 0
-	}
-
-	return dotProduct / (math.Sqrt(magA) * math.Sqrt(magB))
+	return chromaclient.NormalizeVector(embedding)
 }
 
 func main() {
@@ -167,8 +127,8 @@ func main() {
 	test2 := embeddingGen.GenerateEmbedding("The cat sat on the mat")
 	test3 := embeddingGen.GenerateEmbedding("A feline rested on the rug") // Semantically similar
 
-	fmt.Printf("\nSame text similarity: %.4f (should be 1.0)\n", cosineSimilarity(test1, test2))
-	fmt.Printf("Similar meaning similarity: %.4f (would be high with real embeddings)\n", cosineSimilarity(test1, test3))
+	fmt.Printf("\nSame text similarity: %.4f (should be 1.0)\n", chromaclient.CosineSimilarity(test1, test2))
+	fmt.Printf("Similar meaning similarity: %.4f (would be high with real embeddings)\n", chromaclient.CosineSimilarity(test1, test3))
 
 	// Create IDs and metadata
 	ids := make([]string, len(documents))
@@ -226,13 +186,11 @@ func main() {
 	}
 
 	fmt.Println("\nTop 3 Results:")
-	for i := 0; i < len(results.IDs[0]); i++ {
-		fmt.Printf("\n%d. ID: %s\n", i+1, results.IDs[0][i])
-		fmt.Printf("   Document: %s\n", results.Documents[0][i])
-		fmt.Printf("   Distance: %.4f\n", results.Distances[0][i])
-		if i < len(results.Metadatas[0]) {
-			fmt.Printf("   Category: %v\n", results.Metadatas[0][i]["category"])
-		}
+	for i, hit := range results.Group(0) {
+		fmt.Printf("\n%d. ID: %s\n", i+1, hit.ID)
+		fmt.Printf("   Document: %s\n", hit.Document)
+		fmt.Printf("   Distance: %.4f\n", hit.Distance)
+		fmt.Printf("   Category: %v\n", hit.Metadata["category"])
 	}
 
 	// Filter by metadata
@@ -257,9 +215,9 @@ func main() {
 
 	fmt.Printf("Query: %q (category: programming)\n", queryText2)
 	fmt.Println("\nResults:")
-	for i := 0; i < len(results2.IDs[0]); i++ {
-		fmt.Printf("\n%d. Document: %s\n", i+1, results2.Documents[0][i])
-		fmt.Printf("   Distance: %.4f\n", results2.Distances[0][i])
+	for i, hit := range results2.Group(0) {
+		fmt.Printf("\n%d. Document: %s\n", i+1, hit.Document)
+		fmt.Printf("   Distance: %.4f\n", hit.Distance)
 	}
 
 	fmt.Println("\n=== Key Takeaways ===")
@@ -0,0 +1,126 @@
+package chromaclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// AddEmbedding32 is the request body for adding embeddings held as float32,
+// which halves memory for large embedding sets compared to AddEmbedding. It
+// marshals to the same JSON shape as AddEmbedding.
+type AddEmbedding32 struct {
+	IDs        []string                 `json:"ids"`
+	Embeddings [][]float32              `json:"embeddings,omitempty"`
+	Metadatas  []map[string]interface{} `json:"metadatas,omitempty"`
+	Documents  []string                 `json:"documents,omitempty"`
+	Uris       []string                 `json:"uris,omitempty"`
+}
+
+// GetResult32 is a GetResult whose embeddings are decoded as float32, which
+// halves their memory when fetching many vectors
+type GetResult32 struct {
+	IDs        []string                 `json:"ids"`
+	Embeddings [][]float32              `json:"embeddings,omitempty"`
+	Documents  []string                 `json:"documents,omitempty"`
+	Metadatas  []map[string]interface{} `json:"metadatas,omitempty"`
+	Uris       []string                 `json:"uris,omitempty"`
+	Include    []Include                `json:"include"`
+}
+
+// Float32Embeddings converts float64 embeddings to float32
+func Float32Embeddings(embeddings [][]float64) [][]float32 {
+	out := make([][]float32, len(embeddings))
+	for i, e := range embeddings {
+		out[i] = make([]float32, len(e))
+		for j, v := range e {
+			out[i][j] = float32(v)
+		}
+	}
+	return out
+}
+
+// Float64Embeddings converts float32 embeddings to float64
+func Float64Embeddings(embeddings [][]float32) [][]float64 {
+	out := make([][]float64, len(embeddings))
+	for i, e := range embeddings {
+		out[i] = make([]float64, len(e))
+		for j, v := range e {
+			out[i][j] = float64(v)
+		}
+	}
+	return out
+}
+
+// Add32 adds float32 embeddings to a collection. It behaves like Add; the
+// embeddings are sent without being widened to float64.
+func (c *Client) Add32(ctx context.Context, collectionID string, req AddEmbedding32, tenant, database string) error {
+	if len(req.Embeddings) == 0 {
+		return c.Add(ctx, collectionID, req.withoutEmbeddings(), tenant, database)
+	}
+	return writeRecords(c, ctx, "Add", "add", collectionID, req, tenant, database)
+}
+
+// Upsert32 upserts float32 embeddings in a collection. It behaves like
+// Upsert; the embeddings are sent without being widened to float64.
+func (c *Client) Upsert32(ctx context.Context, collectionID string, req AddEmbedding32, tenant, database string) error {
+	if len(req.Embeddings) == 0 {
+		return c.Upsert(ctx, collectionID, req.withoutEmbeddings(), tenant, database)
+	}
+	return writeRecords(c, ctx, "Upsert", "upsert", collectionID, req, tenant, database)
+}
+
+// Get32 gets records like Get but decodes their embeddings as float32, so
+// the float64 values are never materialized
+func (c *Client) Get32(ctx context.Context, collectionID string, req GetEmbedding, tenant, database string) (*GetResult32, error) {
+	if err := c.checkWhere(req.Where); err != nil {
+		return nil, err
+	}
+	if len(req.Include) == 0 {
+		req.Include = c.defaultGetInclude()
+	}
+	if tenant == "" {
+		tenant = c.tenant
+	}
+	if database == "" {
+		database = c.database
+	}
+
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s/get",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(collectionID))
+	var result GetResult32
+	err := c.doRequest(ctx, "Get32", http.MethodPost, path, req, &result)
+	return &result, err
+}
+
+// withoutEmbeddings returns the non-embedding columns of req as an
+// AddEmbedding. Documents-only payloads have nothing to save by staying in
+// float32, so they go through Add and Upsert, including client-side
+// embedding.
+func (req AddEmbedding32) withoutEmbeddings() AddEmbedding {
+	return AddEmbedding{IDs: req.IDs, Metadatas: req.Metadatas, Documents: req.Documents, Uris: req.Uris}
+}
+
+// dedupKeepLast returns a copy of req keeping only the last row of each ID
+func (req AddEmbedding32) dedupKeepLast() AddEmbedding32 {
+	keep := lastOccurrences(req.IDs)
+	if keep == nil {
+		return req
+	}
+	return AddEmbedding32{
+		IDs:        pick(req.IDs, keep),
+		Embeddings: pick(req.Embeddings, keep),
+		Metadatas:  pick(req.Metadatas, keep),
+		Documents:  pick(req.Documents, keep),
+		Uris:       pick(req.Uris, keep),
+	}
+}
+
+// validate and recordIDs let writeRecords handle AddEmbedding32
+func (req AddEmbedding32) validate() error {
+	return validateRecords(len(req.IDs), len(req.Embeddings), len(req.Documents), len(req.Metadatas), len(req.Uris))
+}
+
+func (req AddEmbedding32) recordIDs() []string {
+	return req.IDs
+}
@@ -0,0 +1,137 @@
+package chromaclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdd32MatchesAdd(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123/add" {
+			t.Errorf("Expected add path, got %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+	embeddings := [][]float64{{0.5, -0.25}, {1, 0.125}}
+	meta := []map[string]interface{}{{"k": "v"}, {"k": "w"}}
+
+	if err := client.Add(ctx, "col-123", AddEmbedding{
+		IDs: []string{"a", "b"}, Embeddings: embeddings, Metadatas: meta,
+	}, "", ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := client.Add32(ctx, "col-123", AddEmbedding32{
+		IDs: []string{"a", "b"}, Embeddings: Float32Embeddings(embeddings), Metadatas: meta,
+	}, "", ""); err != nil {
+		t.Fatalf("Add32() error = %v", err)
+	}
+
+	if len(bodies) != 2 || bodies[0] != bodies[1] {
+		t.Errorf("Expected identical request bodies, got %v", bodies)
+	}
+}
+
+func TestUpsert32(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123/upsert" {
+			t.Errorf("Expected upsert path, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.Upsert32(context.Background(), "col-123", AddEmbedding32{
+		IDs:        []string{"a"},
+		Embeddings: [][]float32{{0.1, 0.2}},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Upsert32() error = %v", err)
+	}
+}
+
+func TestAdd32Validation(t *testing.T) {
+	client := NewClient(WithBaseURL("http://127.0.0.1:0"))
+	err := client.Add32(context.Background(), "col-123", AddEmbedding32{
+		IDs:        []string{"a", "b"},
+		Embeddings: [][]float32{{0.1}},
+	}, "", "")
+	if err == nil || err.Error() != "invalid records: Embeddings has 1 entries but IDs has 2" {
+		t.Errorf("Expected column length error, got %v", err)
+	}
+}
+
+func TestFloatEmbeddingConversion(t *testing.T) {
+	in := [][]float64{{0.5, 2}, {}}
+	back := Float64Embeddings(Float32Embeddings(in))
+	if len(back) != 2 || back[0][0] != 0.5 || back[0][1] != 2 || len(back[1]) != 0 {
+		t.Errorf("Expected round trip of %v, got %v", in, back)
+	}
+}
+
+func TestGet32(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123/get" {
+			t.Errorf("Expected get path, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"ids": ["a", "b"], "embeddings": [[0.5, -0.25], [0.1, 2]], "documents": ["doc-a", "doc-b"],
+			"include": ["embeddings", "documents"]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	result, err := client.Get32(context.Background(), "col-123", GetEmbedding{
+		Include: Includes(IncludeEmbeddings, IncludeDocuments),
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Get32() error = %v", err)
+	}
+	if len(result.Embeddings) != 2 || result.Embeddings[0][1] != -0.25 || result.Embeddings[1][0] != float32(0.1) {
+		t.Errorf("Unexpected embeddings %v", result.Embeddings)
+	}
+	if result.Documents[1] != "doc-b" || len(result.Include) != 2 {
+		t.Errorf("Expected other columns to be decoded, got %+v", result)
+	}
+}
+
+func TestAdd32SharesWritePath(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithAutoIdempotencyKey(true))
+	req := AddEmbedding32{IDs: []string{"a"}, Embeddings: [][]float32{{1, 2}}}
+	for i := 0; i < 2; i++ {
+		if err := client.Add32(context.Background(), "col", req, "", ""); err != nil {
+			t.Fatalf("Add32() error = %v", err)
+		}
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Expected Add32 to send the same automatic idempotency key, got %q", keys)
+	}
+	if err := client.Upsert32(context.Background(), "col", req, "", ""); err != nil {
+		t.Fatalf("Upsert32() error = %v", err)
+	}
+	if keys[2] != "" {
+		t.Errorf("Expected no automatic key on upsert, got %q", keys[2])
+	}
+
+	dup := AddEmbedding32{IDs: []string{"a", "a"}, Embeddings: [][]float32{{1}, {2}}}
+	if err := client.Add32(context.Background(), "col", dup, "", ""); err == nil || !strings.Contains(err.Error(), "duplicate IDs") {
+		t.Errorf("Expected Add32 to apply the duplicate ID policy, got %v", err)
+	}
+}
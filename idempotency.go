@@ -0,0 +1,78 @@
+package chromaclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// IdempotencyKeyHeader is the request header carrying an idempotency key.
+// Chroma itself does not deduplicate on it; it is meant for a gateway or
+// proxy in front of the server that replays or drops repeated writes.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// ContextWithIdempotencyKey returns a copy of ctx that makes Add and Upsert
+// send key in the Idempotency-Key header; other requests ignore it. Reuse the
+// same ctx when retrying an Add so every attempt carries the same key. Helpers
+// that write in several batches, such as AddBatched, send key-0, key-1 and
+// so on, so each batch has its own key.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKey returns the key stored in ctx, if any
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// WithAutoIdempotencyKey makes Add send an Idempotency-Key derived from a
+// hash of the records when the context does not already carry one, so that
+// retrying the same batch always produces the same key
+func WithAutoIdempotencyKey(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.autoIdempotencyKey = enabled
+	}
+}
+
+// batchContext returns ctx for the i-th batch of a multi-batch write, whose
+// idempotency key, if any, is suffixed with the batch index
+func batchContext(ctx context.Context, i int) context.Context {
+	if key := idempotencyKey(ctx); key != "" {
+		return ContextWithIdempotencyKey(ctx, key+"-"+strconv.Itoa(i))
+	}
+	return ctx
+}
+
+// idempotencyHeader returns the Idempotency-Key header for a write of req:
+// the key from ctx, or with WithAutoIdempotencyKey a hash of req when auto is
+// set. It returns nil when there is no key.
+func (c *Client) idempotencyHeader(ctx context.Context, req interface{}, auto bool) (http.Header, error) {
+	key := idempotencyKey(ctx)
+	if key == "" && auto && c.autoIdempotencyKey {
+		var err error
+		if key, err = addIdempotencyKey(req); err != nil {
+			return nil, err
+		}
+	}
+	if key == "" {
+		return nil, nil
+	}
+	return http.Header{IdempotencyKeyHeader: {key}}, nil
+}
+
+// addIdempotencyKey hashes the records of an Add request
+func addIdempotencyKey(req interface{}) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
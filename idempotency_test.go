@@ -0,0 +1,97 @@
+package chromaclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	req := AddEmbedding{IDs: []string{"a"}, Embeddings: [][]float64{{1, 2}}}
+	ctx := context.Background()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if err := client.Add(ctx, "col", req, "", ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := client.Add(ContextWithIdempotencyKey(ctx, "batch-1"), "col", req, "", ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if keys[0] != "" || keys[1] != "batch-1" {
+		t.Errorf("Expected no key and then batch-1, got %q", keys)
+	}
+
+	keys = nil
+	client = NewClient(WithBaseURL(server.URL), WithAutoIdempotencyKey(true))
+	other := AddEmbedding{IDs: []string{"a"}, Embeddings: [][]float64{{1, 3}}}
+	for _, r := range []AddEmbedding{req, req, other} {
+		if err := client.Add(ctx, "col", r, "", ""); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Expected identical retries to share a key, got %q", keys)
+	}
+	if keys[2] == keys[0] {
+		t.Error("Expected different embeddings to produce a different key")
+	}
+
+	if err := client.Add(ContextWithIdempotencyKey(ctx, "explicit"), "col", req, "", ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if keys[3] != "explicit" {
+		t.Errorf("Expected context key to take precedence, got %q", keys[3])
+	}
+}
+
+func TestIdempotencyKeyScope(t *testing.T) {
+	keys := map[string][]string{}
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		op := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		keys[op] = append(keys[op], r.Header.Get(IdempotencyKeyHeader))
+		w.Write([]byte(`{"ids": []}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := ContextWithIdempotencyKey(context.Background(), "ingest")
+	req := AddEmbedding{IDs: []string{"a", "b", "c"}, Documents: []string{"1", "2", "3"}}
+
+	if err := client.AddBatched(ctx, "col", req, 2, "", ""); err != nil {
+		t.Fatalf("AddBatched() error = %v", err)
+	}
+	if want := []string{"ingest-0", "ingest-1"}; !slices.Equal(keys["add"], want) {
+		t.Errorf("Expected per-batch keys %v, got %v", want, keys["add"])
+	}
+	if err := client.UpsertConcurrent(ctx, "col", req, 2, 2, "", ""); err != nil {
+		t.Fatalf("UpsertConcurrent() error = %v", err)
+	}
+	upserts := slices.Sorted(slices.Values(keys["upsert"]))
+	if want := []string{"ingest-0", "ingest-1"}; !slices.Equal(upserts, want) {
+		t.Errorf("Expected per-batch keys %v, got %v", want, upserts)
+	}
+
+	if _, err := client.Get(ctx, "col", GetEmbedding{}, "", ""); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if err := client.Delete(ctx, "col", DeleteEmbedding{IDs: []string{"a"}}, "", ""); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if keys["get"][0] != "" || keys["delete"][0] != "" {
+		t.Errorf("Expected reads and deletes not to carry the key, got %q and %q", keys["get"][0], keys["delete"][0])
+	}
+}
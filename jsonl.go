@@ -0,0 +1,169 @@
+package chromaclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// jsonlRecord is one line of the JSON Lines format written by
+// ExportCollection. Empty fields are omitted.
+type jsonlRecord struct {
+	ID        string                 `json:"id"`
+	Document  string                 `json:"document,omitempty"`
+	Embedding []float64              `json:"embedding,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	URI       string                 `json:"uri,omitempty"`
+}
+
+// shape records which optional fields a record carries. Rows in one upsert
+// batch must share a shape so every column has one entry per ID.
+type shape struct {
+	document, embedding, metadata, uri bool
+}
+
+func (r jsonlRecord) shape() shape {
+	return shape{
+		document:  r.Document != "",
+		embedding: len(r.Embedding) > 0,
+		metadata:  len(r.Metadata) > 0,
+		uri:       r.URI != "",
+	}
+}
+
+// ExportCollection writes every record of a collection to w as JSON Lines,
+// one object per record with its id, document, embedding, metadata and uri.
+// Records are fetched pageSize at a time and written as they arrive, so
+// memory use does not grow with the collection.
+func (c *Client) ExportCollection(ctx context.Context, collectionID string, w io.Writer, pageSize int, tenant, database string) error {
+	enc := json.NewEncoder(w)
+	req := GetEmbedding{
+		Include: Includes(IncludeEmbeddings, IncludeDocuments, IncludeMetadatas, IncludeUris),
+	}
+	return c.GetAllFunc(ctx, collectionID, req, pageSize, func(page *GetResult) error {
+		for _, row := range page.Rows() {
+			err := enc.Encode(jsonlRecord{
+				ID:        row.ID,
+				Document:  row.Document,
+				Embedding: row.Embedding,
+				Metadata:  row.Metadata,
+				URI:       row.URI,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to write record %s: %w", row.ID, err)
+			}
+		}
+		return nil
+	}, tenant, database)
+}
+
+// ImportCollection reads JSON Lines in the format written by ExportCollection
+// and upserts the records into a collection, batchSize at a time. If
+// batchSize is 0 the server's max_batch_size from PreFlightChecks is used.
+// Optional fields may be missing; a record without an embedding is embedded by
+// the client's embedding function, if any.
+//
+// Lines that cannot be parsed are skipped and reported together, each
+// prefixed with its line number, once the rest of the input is imported. An
+// upsert failure stops the import.
+func (c *Client) ImportCollection(ctx context.Context, collectionID string, r io.Reader, batchSize int, tenant, database string) error {
+	batchSize, err := c.resolveBatchSize(ctx, batchSize)
+	if err != nil {
+		return err
+	}
+	if batchSize <= 0 {
+		return fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+
+	var (
+		batch     AddEmbedding
+		batchFrom int
+		batches   int
+		current   shape
+		errs      []error
+	)
+	flush := func(to int) error {
+		if len(batch.IDs) == 0 {
+			return nil
+		}
+		err := c.Upsert(batchContext(ctx, batches), collectionID, batch, tenant, database)
+		batches++
+		if err != nil {
+			return fmt.Errorf("failed to import lines %d-%d: %w", batchFrom, to, err)
+		}
+		batch = AddEmbedding{}
+		return nil
+	}
+
+	br := bufio.NewReader(r)
+	line, last := 0, 0
+	for {
+		data, readErr := br.ReadBytes('\n')
+		if len(data) > 0 {
+			line++
+		}
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			rec, err := parseRecord(data)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			} else {
+				if len(batch.IDs) > 0 && (rec.shape() != current || len(batch.IDs) == batchSize) {
+					if err := flush(last); err != nil {
+						return err
+					}
+				}
+				if len(batch.IDs) == 0 {
+					batchFrom, current = line, rec.shape()
+				}
+				appendRecord(&batch, rec)
+				last = line
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read line %d: %w", line, readErr)
+		}
+	}
+	if err := flush(last); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// parseRecord decodes one JSON Lines record and checks that it can be upserted
+func parseRecord(data []byte) (jsonlRecord, error) {
+	var rec jsonlRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, err
+	}
+	if rec.ID == "" {
+		return rec, fmt.Errorf("missing id")
+	}
+	if rec.Document == "" && len(rec.Embedding) == 0 {
+		return rec, fmt.Errorf("record %s needs an embedding or a document", rec.ID)
+	}
+	return rec, nil
+}
+
+// appendRecord adds rec to batch, filling only the columns rec carries
+func appendRecord(batch *AddEmbedding, rec jsonlRecord) {
+	batch.IDs = append(batch.IDs, rec.ID)
+	if rec.Document != "" {
+		batch.Documents = append(batch.Documents, rec.Document)
+	}
+	if len(rec.Embedding) > 0 {
+		batch.Embeddings = append(batch.Embeddings, rec.Embedding)
+	}
+	if len(rec.Metadata) > 0 {
+		batch.Metadatas = append(batch.Metadatas, rec.Metadata)
+	}
+	if rec.URI != "" {
+		batch.Uris = append(batch.Uris, rec.URI)
+	}
+}
@@ -0,0 +1,134 @@
+package chromaclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExportCollection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GetEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if len(req.Include) != 4 {
+			t.Errorf("Expected embeddings, documents, metadatas and uris to be included, got %v", req.Include)
+		}
+		if *req.Offset > 0 {
+			json.NewEncoder(w).Encode(GetResult{})
+			return
+		}
+		json.NewEncoder(w).Encode(GetResult{
+			IDs:        []string{"id1", "id2"},
+			Embeddings: [][]float64{{0.1, 0.2}, {0.3, 0.4}},
+			Documents:  []string{"doc1", ""},
+			Metadatas:  []map[string]interface{}{{"k": "v"}, nil},
+			Uris:       []string{"", "s3://bucket/id2"},
+		})
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(WithBaseURL(server.URL))
+	if err := client.ExportCollection(context.Background(), "col-123", &buf, 2, "", ""); err != nil {
+		t.Fatalf("ExportCollection() error = %v", err)
+	}
+
+	want := `{"id":"id1","document":"doc1","embedding":[0.1,0.2],"metadata":{"k":"v"}}
+{"id":"id2","embedding":[0.3,0.4],"uri":"s3://bucket/id2"}
+`
+	if buf.String() != want {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+func TestImportCollection(t *testing.T) {
+	var batches []AddEmbedding
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tenants/default_tenant/databases/default_database/collections/col-123/upsert" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		var req AddEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		batches = append(batches, req)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	input := `{"id":"id1","document":"doc1","embedding":[0.1,0.2],"metadata":{"k":"v"}}
+{"id":"id2","document":"doc2","embedding":[0.3,0.4],"metadata":{"k":"w"}}
+{"id":"id3","document":"doc3","embedding":[0.5,0.6],"metadata":{"k":"x"}}
+not json
+
+{"id":"id4","document":"doc4"}
+{"document":"no id"}
+{"id":"id5","embedding":[0.7,0.8],"uri":"s3://bucket/id5"}
+`
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.ImportCollection(context.Background(), "col-123", strings.NewReader(input), 2, "", "")
+	if err == nil {
+		t.Fatal("Expected parse errors, got nil")
+	}
+	for _, want := range []string{"line 4:", "line 7: missing id"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q, got %v", want, err)
+		}
+	}
+
+	want := [][]string{{"id1", "id2"}, {"id3"}, {"id4"}, {"id5"}}
+	if len(batches) != len(want) {
+		t.Fatalf("Expected %d batches, got %d: %v", len(want), len(batches), batches)
+	}
+	for i, ids := range want {
+		if fmt.Sprint(batches[i].IDs) != fmt.Sprint(ids) {
+			t.Errorf("Expected batch %d to have ids %v, got %v", i, ids, batches[i].IDs)
+		}
+	}
+	if len(batches[2].Embeddings) != 0 || len(batches[2].Documents) != 1 {
+		t.Errorf("Expected document-only batch, got %+v", batches[2])
+	}
+	if len(batches[3].Uris) != 1 || len(batches[3].Documents) != 0 {
+		t.Errorf("Expected embedding and uri batch, got %+v", batches[3])
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	rows := GetResult{
+		IDs:        []string{"id1", "id2"},
+		Embeddings: [][]float64{{0.1, 0.2}, {0.3, 0.4}},
+		Documents:  []string{"doc1", "doc2"},
+		Metadatas:  []map[string]interface{}{{"k": "v"}, {"k": "w"}},
+	}
+	var imported AddEmbedding
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/get") {
+			json.NewEncoder(w).Encode(rows)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&imported)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(WithBaseURL(server.URL))
+	if err := client.ExportCollection(context.Background(), "src", &buf, 10, "", ""); err != nil {
+		t.Fatalf("ExportCollection() error = %v", err)
+	}
+	if err := client.ImportCollection(context.Background(), "dst", &buf, 10, "", ""); err != nil {
+		t.Fatalf("ImportCollection() error = %v", err)
+	}
+
+	if fmt.Sprint(imported.IDs, imported.Embeddings, imported.Documents, imported.Metadatas) !=
+		fmt.Sprint(rows.IDs, rows.Embeddings, rows.Documents, rows.Metadatas) {
+		t.Errorf("Expected imported records to match exported ones, got %+v", imported)
+	}
+}
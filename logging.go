@@ -0,0 +1,150 @@
+package chromaclient
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxLoggedBody is the number of request body bytes included in a log entry
+const maxLoggedBody = 4096
+
+const redacted = "[REDACTED]"
+
+// WithLogger sets the logger used for client diagnostics. Every request is
+// logged at debug level with its method, path, response status and duration;
+// failed requests include the error. Logging is off by default.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithSlowRequestThreshold logs, at warning level, every request that takes
+// longer than d. It is a no-op unless a logger is set with WithLogger.
+func WithSlowRequestThreshold(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.slowThreshold = d
+	}
+}
+
+// WithRequestBodyLogging adds request headers and the request body, truncated
+// to 4KB, to each request's debug log entry. Authorization headers and
+// token-, key-, password- and secret-like JSON fields are redacted. It is a
+// no-op unless a logger is set with WithLogger.
+func WithRequestBodyLogging(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.logBodies = enabled
+	}
+}
+
+// RequestIDHeader is the request header carrying the ID generated by the
+// function set with WithRequestIDGenerator
+const RequestIDHeader = "X-Request-ID"
+
+// WithRequestIDGenerator tags every request with an X-Request-ID header from
+// fn, for correlating client errors with server logs. The ID is also set on
+// returned HTTPErrors and included in log output.
+func WithRequestIDGenerator(fn func() string) ClientOption {
+	return func(c *Client) {
+		c.requestID = fn
+	}
+}
+
+// logRequest logs a completed request. status is 0 if no response was
+// received.
+func (c *Client) logRequest(req *http.Request, path string, body []byte, status int, d time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	method := req.Method
+	var idAttrs []any
+	if id := req.Header.Get(RequestIDHeader); id != "" {
+		idAttrs = []any{"request_id", id}
+	}
+	if c.slowThreshold > 0 && d > c.slowThreshold {
+		c.logger.Warn("slow chroma request", append([]any{"method", method, "path", path, "duration", d}, idAttrs...)...)
+	}
+
+	ctx := req.Context()
+	if !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := append([]any{"method", method, "path", path, "status", status, "duration", d}, idAttrs...)
+	if c.logBodies {
+		attrs = append(attrs, "headers", redactHeaders(req.Header))
+		if len(body) > 0 {
+			attrs = append(attrs, "body", redactBody(body))
+		}
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	c.logger.DebugContext(ctx, "chroma request", attrs...)
+}
+
+// redactHeaders returns h flattened for logging, with credentials redacted
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if isSensitive(k) {
+			out[k] = redacted
+			continue
+		}
+		out[k] = strings.Join(v, ", ")
+	}
+	return out
+}
+
+// redactBody returns a JSON body for logging with sensitive fields redacted.
+// Bodies that are not valid JSON are not logged.
+func redactBody(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "<non-JSON body>"
+	}
+
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return "<unloggable body>"
+	}
+	if len(out) > maxLoggedBody {
+		return string(out[:maxLoggedBody]) + "...(truncated)"
+	}
+	return string(out)
+}
+
+// redactValue replaces the values of sensitive object keys, recursively
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if isSensitive(k) {
+				v[k] = redacted
+				continue
+			}
+			v[k] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return v
+}
+
+// isSensitive reports whether a header or field name looks like it holds a
+// credential
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range []string{"authorization", "token", "api_key", "apikey", "password", "secret"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
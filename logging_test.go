@@ -0,0 +1,132 @@
+package chromaclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(WithBaseURL(server.URL), WithLogger(logger), WithAuthToken("secret-token"))
+
+	err := client.Add(context.Background(), "col-123", AddEmbedding{
+		IDs:       []string{"a"},
+		Documents: []string{"hello"},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{`msg="chroma request"`, "method=POST", "/collections/col-123/add", "status=201", "duration="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected log to contain %q, got: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "hello") || strings.Contains(out, "secret-token") {
+		t.Errorf("Expected body and token to be omitted by default, got: %s", out)
+	}
+}
+
+func TestRequestBodyLoggingRedacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("bad where"))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithLogger(logger),
+		WithRequestBodyLogging(true),
+		WithAuthToken("secret-token"),
+	)
+
+	_, err := client.Get(context.Background(), "col-123", GetEmbedding{
+		Where: map[string]interface{}{"category": "tech", "api_token": "hunter2"},
+	}, "", "")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	out := buf.String()
+	for _, want := range []string{"category", "tech", "status=400", "error=", redacted} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected log to contain %q, got: %s", want, out)
+		}
+	}
+	for _, leaked := range []string{"secret-token", "hunter2"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("Expected %q to be redacted, got: %s", leaked, out)
+		}
+	}
+}
+
+func TestRequestLoggingRespectsLevel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"1.0.0"`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	client := NewClient(WithBaseURL(server.URL), WithLogger(logger))
+
+	if _, err := client.Version(context.Background()); err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output at info level, got: %s", buf.String())
+	}
+}
+
+func TestRequestIDGenerator(t *testing.T) {
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get(RequestIDHeader))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	n := 0
+	client := NewClient(WithBaseURL(server.URL), WithLogger(logger), WithRequestIDGenerator(func() string {
+		n++
+		return fmt.Sprintf("req-%d", n)
+	}))
+
+	_, err := client.GetCollection(context.Background(), "missing", "", "")
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.RequestID != "req-1" {
+		t.Fatalf("Expected HTTPError with request ID req-1, got %v", err)
+	}
+	client.GetCollection(context.Background(), "missing", "", "")
+	if fmt.Sprint(seen) != "[req-1 req-2]" {
+		t.Errorf("Expected a new ID per request, got %v", seen)
+	}
+	if !strings.Contains(buf.String(), "request_id=req-1") {
+		t.Errorf("Expected log to contain the request ID, got: %s", buf.String())
+	}
+
+	seen = nil
+	NewClient(WithBaseURL(server.URL)).GetCollection(context.Background(), "missing", "", "")
+	if seen[0] != "" {
+		t.Errorf("Expected no request ID by default, got %q", seen[0])
+	}
+}
@@ -0,0 +1,174 @@
+package chromaclient
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Metadata is a metadata record as stored on a collection row. It is a plain
+// map, so it can be used anywhere a map[string]interface{} is expected, but it
+// adds typed accessors for values that come back from JSON in loosely typed
+// form.
+type Metadata map[string]interface{}
+
+// MetaStringList returns the list value stored under key as a []string.
+// It reports false if the key is missing or any element is not a string.
+func (m Metadata) MetaStringList(key string) ([]string, bool) {
+	switch v := m[key].(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		out := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out[i] = s
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+// MetaFloatList returns the list value stored under key as a []float64.
+// It reports false if the key is missing or any element is not numeric.
+func (m Metadata) MetaFloatList(key string) ([]float64, bool) {
+	switch v := m[key].(type) {
+	case []float64:
+		return v, true
+	case []interface{}:
+		out := make([]float64, len(v))
+		for i, item := range v {
+			f, ok := toFloat64(item)
+			if !ok {
+				return nil, false
+			}
+			out[i] = f
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+// SetStringList stores a copy of values under key, so later changes to the
+// caller's slice do not leak into a pending Add.
+func (m Metadata) SetStringList(key string, values []string) {
+	m[key] = append([]string(nil), values...)
+}
+
+// SetFloatList stores a copy of values under key, so later changes to the
+// caller's slice do not leak into a pending Add.
+func (m Metadata) SetFloatList(key string, values []float64) {
+	m[key] = append([]float64(nil), values...)
+}
+
+// GetString returns the string stored under key, like MetadataString
+func (m Metadata) GetString(key string) (string, bool) {
+	return MetadataString(m, key)
+}
+
+// GetInt returns the integer stored under key, like MetadataInt, so a whole
+// number decoded from JSON as a float64 comes back as an int64
+func (m Metadata) GetInt(key string) (int64, bool) {
+	return MetadataInt(m, key)
+}
+
+// GetFloat returns the number stored under key as a float64, like
+// MetadataFloat
+func (m Metadata) GetFloat(key string) (float64, bool) {
+	return MetadataFloat(m, key)
+}
+
+// GetBool returns the bool stored under key, like MetadataBool
+func (m Metadata) GetBool(key string) (value, ok bool) {
+	return MetadataBool(m, key)
+}
+
+// SetString stores a string under key
+func (m Metadata) SetString(key, value string) {
+	m[key] = value
+}
+
+// SetInt stores an integer under key
+func (m Metadata) SetInt(key string, value int64) {
+	m[key] = value
+}
+
+// SetFloat stores a float under key
+func (m Metadata) SetFloat(key string, value float64) {
+	m[key] = value
+}
+
+// SetBool stores a bool under key
+func (m Metadata) SetBool(key string, value bool) {
+	m[key] = value
+}
+
+// MetadataInt returns the integer stored under key. It accepts json.Number
+// values, as decoded with WithUseNumber, without loss of precision, as well
+// as float64 values that hold a whole number. It reports false if the key is
+// missing or the value is not an integer.
+func MetadataInt(m map[string]interface{}, key string) (int64, bool) {
+	switch n := m[key].(type) {
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// MetadataFloat returns the number stored under key as a float64. It reports
+// false if the key is missing or the value is not numeric.
+func MetadataFloat(m map[string]interface{}, key string) (float64, bool) {
+	return toFloat64(m[key])
+}
+
+// MetadataString returns the string stored under key. It reports false if the
+// key is missing or the value is not a string.
+func MetadataString(m map[string]interface{}, key string) (string, bool) {
+	s, ok := m[key].(string)
+	return s, ok
+}
+
+// MetadataBool returns the bool stored under key. It reports false if the key
+// is missing or the value is not a bool.
+func MetadataBool(m map[string]interface{}, key string) (value, ok bool) {
+	value, ok = m[key].(bool)
+	return value, ok
+}
+
+// toFloat64 converts the numeric types that can appear in metadata to float64
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,176 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestMetadataReadLists(t *testing.T) {
+	var result GetResult
+	body := `{"ids":["id1"],"metadatas":[{"tags":["go","db"],"scores":[1,2.5],"mixed":["a",1]}],"include":["metadatas"]}`
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	meta := Metadata(result.Metadatas[0])
+
+	tags, ok := meta.MetaStringList("tags")
+	if !ok || !reflect.DeepEqual(tags, []string{"go", "db"}) {
+		t.Errorf("Expected tags [go db], got %v (ok=%v)", tags, ok)
+	}
+
+	scores, ok := meta.MetaFloatList("scores")
+	if !ok || !reflect.DeepEqual(scores, []float64{1, 2.5}) {
+		t.Errorf("Expected scores [1 2.5], got %v (ok=%v)", scores, ok)
+	}
+
+	if _, ok := meta.MetaStringList("mixed"); ok {
+		t.Error("Expected mixed list not to coerce to []string")
+	}
+	if _, ok := meta.MetaFloatList("tags"); ok {
+		t.Error("Expected string list not to coerce to []float64")
+	}
+	if _, ok := meta.MetaStringList("missing"); ok {
+		t.Error("Expected missing key to report false")
+	}
+}
+
+func TestMetadataWriteLists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Metadatas []map[string]json.RawMessage `json:"metadatas"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if got := string(req.Metadatas[0]["tags"]); got != `["go","db"]` {
+			t.Errorf("Expected tags to be sent as a JSON array, got %s", got)
+		}
+		if got := string(req.Metadatas[0]["scores"]); got != `[0.5,1]` {
+			t.Errorf("Expected scores to be sent as a JSON array, got %s", got)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	tags := []string{"go", "db"}
+	meta := Metadata{}
+	meta.SetStringList("tags", tags)
+	meta.SetFloatList("scores", []float64{0.5, 1})
+	tags[0] = "changed"
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.Add(context.Background(), "col-123", AddEmbedding{
+		IDs:       []string{"id1"},
+		Documents: []string{"doc1"},
+		Metadatas: []map[string]interface{}{meta},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+}
+
+func TestMetadataAccessors(t *testing.T) {
+	m := map[string]interface{}{
+		"id":    json.Number("9007199254740993"),
+		"count": 3.0,
+		"ratio": 0.5,
+		"name":  "chroma",
+		"ok":    true,
+	}
+
+	if v, ok := MetadataInt(m, "id"); !ok || v != 9007199254740993 {
+		t.Errorf("Expected exact int 9007199254740993, got %d (%v)", v, ok)
+	}
+	if v, ok := MetadataInt(m, "count"); !ok || v != 3 {
+		t.Errorf("Expected 3, got %d (%v)", v, ok)
+	}
+	if _, ok := MetadataInt(m, "ratio"); ok {
+		t.Error("Expected fractional float not to be an int")
+	}
+	if v, ok := MetadataFloat(m, "ratio"); !ok || v != 0.5 {
+		t.Errorf("Expected 0.5, got %v (%v)", v, ok)
+	}
+	if v, ok := MetadataString(m, "name"); !ok || v != "chroma" {
+		t.Errorf("Expected chroma, got %q (%v)", v, ok)
+	}
+	if v, ok := MetadataBool(m, "ok"); !ok || !v {
+		t.Errorf("Expected true, got %v (%v)", v, ok)
+	}
+	if _, ok := MetadataString(m, "missing"); ok {
+		t.Error("Expected missing key to report false")
+	}
+}
+
+func TestMetadataTypedMethods(t *testing.T) {
+	m := Metadata{}
+	m.SetString("name", "chroma")
+	m.SetInt("count", 3)
+	m.SetFloat("ratio", 0.5)
+	m.SetBool("ok", true)
+
+	// Round-trip through JSON, where the int comes back as a float64
+	data, err := json.Marshal(CreateCollection{Name: "docs", Metadata: m})
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	var col Collection
+	if err := json.Unmarshal(data, &col); err != nil {
+		t.Fatalf("Failed to unmarshal collection: %v", err)
+	}
+	if _, isFloat := col.Metadata["count"].(float64); !isFloat {
+		t.Fatalf("Expected count to decode as float64, got %T", col.Metadata["count"])
+	}
+
+	if v, ok := col.Metadata.GetInt("count"); !ok || v != 3 {
+		t.Errorf("Expected 3, got %d (%v)", v, ok)
+	}
+	if v, ok := col.Metadata.GetFloat("ratio"); !ok || v != 0.5 {
+		t.Errorf("Expected 0.5, got %v (%v)", v, ok)
+	}
+	if v, ok := col.Metadata.GetString("name"); !ok || v != "chroma" {
+		t.Errorf("Expected chroma, got %q (%v)", v, ok)
+	}
+	if v, ok := col.Metadata.GetBool("ok"); !ok || !v {
+		t.Errorf("Expected true, got %v (%v)", v, ok)
+	}
+	if _, ok := col.Metadata.GetInt("name"); ok {
+		t.Error("Expected string value not to be an int")
+	}
+}
+
+func TestWithUseNumber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ids": ["a"], "metadatas": [{"big": 9007199254740993}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		useNumber bool
+		want      int64
+	}{
+		{"off", false, 9007199254740992},
+		{"on", true, 9007199254740993},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(WithBaseURL(server.URL), WithUseNumber(tt.useNumber))
+			result, err := client.Get(context.Background(), "col-123", GetEmbedding{}, "", "")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			got, ok := MetadataInt(result.Metadatas[0], "big")
+			if !ok || got != tt.want {
+				t.Errorf("Expected %d, got %d (%v)", tt.want, got, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package chromaclient
+
+import "time"
+
+// Metrics receives one observation per HTTP request, for adapting to
+// Prometheus or another metrics system. op is the client method that issued
+// the request, such as "Query". status is 0 when no response was received.
+type Metrics interface {
+	ObserveRequest(op string, status int, d time.Duration)
+}
+
+// WithMetrics reports every request to m
+func WithMetrics(m Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
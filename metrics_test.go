@@ -0,0 +1,65 @@
+package chromaclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type recordedRequest struct {
+	op     string
+	status int
+	d      time.Duration
+}
+
+type recordingMetrics struct {
+	requests []recordedRequest
+}
+
+func (m *recordingMetrics) ObserveRequest(op string, status int, d time.Duration) {
+	m.requests = append(m.requests, recordedRequest{op, status, d})
+}
+
+func TestWithMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/version" {
+			w.Write([]byte(`"1.0.0"`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	metrics := &recordingMetrics{}
+	client := NewClient(WithBaseURL(server.URL), WithMetrics(metrics))
+	ctx := context.Background()
+	client.Version(ctx)
+	client.GetCollection(ctx, "missing", "", "")
+	client.ListDatabases(ctx, "")
+	client.ListCollections(ctx, "", "")
+	client.ListCollectionsPaged(ctx, "", "", 10, 0)
+	server.Close()
+	client.Version(ctx)
+
+	want := []recordedRequest{
+		{op: "Version", status: http.StatusOK},
+		{op: "GetCollection", status: http.StatusNotFound},
+		{op: "ListDatabases", status: http.StatusNotFound},
+		{op: "ListCollections", status: http.StatusNotFound},
+		{op: "ListCollectionsPaged", status: http.StatusNotFound},
+		{op: "Version", status: 0},
+	}
+	if len(metrics.requests) != len(want) {
+		t.Fatalf("Expected %d observations, got %+v", len(want), metrics.requests)
+	}
+	for i, w := range want {
+		got := metrics.requests[i]
+		if got.op != w.op || got.status != w.status {
+			t.Errorf("Observation %d: expected %s %d, got %s %d", i, w.op, w.status, got.op, got.status)
+		}
+		if got.d <= 0 {
+			t.Errorf("Observation %d: expected a positive duration, got %v", i, got.d)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package chromaclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OllamaEmbeddingFunction generates embeddings with a local Ollama server's
+// /api/embeddings endpoint, which embeds one text per request
+type OllamaEmbeddingFunction struct {
+	baseURL     string
+	model       string
+	httpClient  *http.Client
+	parallelism int
+}
+
+// OllamaOption is a function that configures an OllamaEmbeddingFunction
+type OllamaOption func(*OllamaEmbeddingFunction)
+
+// WithOllamaBaseURL sets the Ollama server URL
+func WithOllamaBaseURL(baseURL string) OllamaOption {
+	return func(f *OllamaEmbeddingFunction) {
+		f.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithOllamaHTTPClient sets a custom HTTP client
+func WithOllamaHTTPClient(httpClient *http.Client) OllamaOption {
+	return func(f *OllamaEmbeddingFunction) {
+		f.httpClient = httpClient
+	}
+}
+
+// WithOllamaParallelism sets how many texts are embedded concurrently
+func WithOllamaParallelism(n int) OllamaOption {
+	return func(f *OllamaEmbeddingFunction) {
+		f.parallelism = n
+	}
+}
+
+// NewOllamaEmbeddingFunction creates an embedding function for the given
+// Ollama model, e.g. "nomic-embed-text". The server defaults to
+// http://localhost:11434.
+func NewOllamaEmbeddingFunction(model string, opts ...OllamaOption) *OllamaEmbeddingFunction {
+	f := &OllamaEmbeddingFunction{
+		baseURL: "http://localhost:11434",
+		model:   model,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		parallelism: 1,
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// Model returns the configured embedding model
+func (f *OllamaEmbeddingFunction) Model() string {
+	return f.model
+}
+
+type ollamaEmbeddingRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+type ollamaEmbeddingResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+// Embed generates one embedding per text, sending up to the configured
+// parallelism requests at a time. The first failure cancels the rest.
+func (f *OllamaEmbeddingFunction) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	embeddings := make([][]float64, len(texts))
+	err := runParallel(ctx, len(texts), f.parallelism, true, "text", func(ctx context.Context, i int) error {
+		embedding, err := f.embedOne(ctx, texts[i])
+		if err != nil {
+			return err
+		}
+		embeddings[i] = embedding
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings, nil
+}
+
+// embedOne embeds a single text
+func (f *OllamaEmbeddingFunction) embedOne(ctx context.Context, text string) ([]float64, error) {
+	jsonData, err := json.Marshal(ollamaEmbeddingRequest{Model: f.model, Prompt: text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.baseURL+"/api/embeddings", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			Message:    string(body),
+			Timestamp:  time.Now(),
+		}
+	}
+
+	var result ollamaEmbeddingResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(result.Embedding) == 0 {
+		return nil, fmt.Errorf("ollama returned an empty embedding")
+	}
+	return result.Embedding, nil
+}
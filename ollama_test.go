@@ -0,0 +1,76 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOllamaEmbeddingFunction(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embeddings" {
+			t.Errorf("Expected path /api/embeddings, got %s", r.URL.Path)
+		}
+
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var req ollamaEmbeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if req.Model != "nomic-embed-text" {
+			t.Errorf("Expected model nomic-embed-text, got %s", req.Model)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ollamaEmbeddingResponse{Embedding: []float64{float64(len(req.Prompt))}})
+	}))
+	defer server.Close()
+
+	ef := NewOllamaEmbeddingFunction("nomic-embed-text",
+		WithOllamaBaseURL(server.URL),
+		WithOllamaParallelism(2),
+	)
+	embeddings, err := ef.Embed(context.Background(), []string{"a", "bb", "ccc", "dddd"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	for i, e := range embeddings {
+		if e[0] != float64(i+1) {
+			t.Errorf("Expected embedding %d to be [%d], got %v", i, i+1, e)
+		}
+	}
+	if m := maxInFlight.Load(); m > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", m)
+	}
+}
+
+func TestOllamaEmbeddingFunctionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model not found"}`))
+	}))
+	defer server.Close()
+
+	ef := NewOllamaEmbeddingFunction("missing-model", WithOllamaBaseURL(server.URL))
+	_, err := ef.Embed(context.Background(), []string{"text", "more"})
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || !strings.HasPrefix(err.Error(), "text 0: ") {
+		t.Fatalf("Expected the first text's HTTP error, got %v", err)
+	}
+}
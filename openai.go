@@ -0,0 +1,172 @@
+package chromaclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultOpenAIModel is the embedding model used by OpenAIEmbeddingFunction
+// when none is configured
+const DefaultOpenAIModel = "text-embedding-3-small"
+
+// OpenAIEmbeddingFunction generates embeddings with OpenAI's embeddings API
+type OpenAIEmbeddingFunction struct {
+	apiKey       string
+	model        string
+	baseURL      string
+	httpClient   *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+// OpenAIOption is a function that configures an OpenAIEmbeddingFunction
+type OpenAIOption func(*OpenAIEmbeddingFunction)
+
+// WithOpenAIModel sets the embedding model
+func WithOpenAIModel(model string) OpenAIOption {
+	return func(f *OpenAIEmbeddingFunction) {
+		f.model = model
+	}
+}
+
+// WithOpenAIBaseURL sets the API base URL, for OpenAI-compatible servers
+func WithOpenAIBaseURL(baseURL string) OpenAIOption {
+	return func(f *OpenAIEmbeddingFunction) {
+		f.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithOpenAIHTTPClient sets a custom HTTP client
+func WithOpenAIHTTPClient(httpClient *http.Client) OpenAIOption {
+	return func(f *OpenAIEmbeddingFunction) {
+		f.httpClient = httpClient
+	}
+}
+
+// WithOpenAIMaxRetries sets how many times a rate-limited (429) or failed
+// (5xx) request is retried
+func WithOpenAIMaxRetries(n int) OpenAIOption {
+	return func(f *OpenAIEmbeddingFunction) {
+		f.maxRetries = n
+	}
+}
+
+// NewOpenAIEmbeddingFunction creates an embedding function for OpenAI
+func NewOpenAIEmbeddingFunction(apiKey string, opts ...OpenAIOption) *OpenAIEmbeddingFunction {
+	f := &OpenAIEmbeddingFunction{
+		apiKey:  apiKey,
+		model:   DefaultOpenAIModel,
+		baseURL: "https://api.openai.com/v1",
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		maxRetries:   2,
+		retryBackoff: 500 * time.Millisecond,
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// Model returns the configured embedding model
+func (f *OpenAIEmbeddingFunction) Model() string {
+	return f.model
+}
+
+type openAIEmbeddingRequest struct {
+	Input []string `json:"input"`
+	Model string   `json:"model"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+}
+
+// Embed generates one embedding per text
+func (f *OpenAIEmbeddingFunction) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	jsonData, err := json.Marshal(openAIEmbeddingRequest{Input: texts, Model: f.model})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	var body []byte
+	for attempt := 0; ; attempt++ {
+		var status int
+		body, status, err = f.post(ctx, jsonData)
+		if err != nil {
+			return nil, err
+		}
+		if status == http.StatusOK {
+			break
+		}
+
+		retryable := status == http.StatusTooManyRequests || status >= 500
+		if !retryable || attempt >= f.maxRetries {
+			return nil, &HTTPError{
+				StatusCode: status,
+				Message:    string(body),
+				Timestamp:  time.Now(),
+			}
+		}
+
+		select {
+		case <-time.After(f.retryBackoff << attempt):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	var resp openAIEmbeddingResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	embeddings := make([][]float64, len(texts))
+	for _, data := range resp.Data {
+		if data.Index < 0 || data.Index >= len(embeddings) {
+			return nil, fmt.Errorf("openai returned embedding for out-of-range index %d", data.Index)
+		}
+		embeddings[data.Index] = data.Embedding
+	}
+	for i, e := range embeddings {
+		if e == nil {
+			return nil, fmt.Errorf("openai returned no embedding for input %d", i)
+		}
+	}
+
+	return embeddings, nil
+}
+
+// post sends a single embeddings request and returns the body and status
+func (f *OpenAIEmbeddingFunction) post(ctx context.Context, jsonData []byte) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.baseURL+"/embeddings", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+f.apiKey)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
@@ -0,0 +1,91 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOpenAIEmbeddingFunction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("Expected path /embeddings, got %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Expected Authorization header Bearer test-key, got %s", got)
+		}
+
+		var req openAIEmbeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if req.Model != "text-embedding-3-large" {
+			t.Errorf("Expected model text-embedding-3-large, got %s", req.Model)
+		}
+
+		// Return the embeddings out of order to check they are reordered by index.
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[{"embedding":[0.2],"index":1},{"embedding":[0.1],"index":0}]}`))
+	}))
+	defer server.Close()
+
+	ef := NewOpenAIEmbeddingFunction("test-key",
+		WithOpenAIBaseURL(server.URL),
+		WithOpenAIModel("text-embedding-3-large"),
+	)
+	embeddings, err := ef.Embed(context.Background(), []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(embeddings) != 2 || embeddings[0][0] != 0.1 || embeddings[1][0] != 0.2 {
+		t.Errorf("Expected embeddings [[0.1] [0.2]], got %v", embeddings)
+	}
+}
+
+func TestOpenAIEmbeddingFunctionRetries(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[{"embedding":[0.1],"index":0}]}`))
+	}))
+	defer server.Close()
+
+	ef := NewOpenAIEmbeddingFunction("test-key", WithOpenAIBaseURL(server.URL))
+	ef.retryBackoff = time.Millisecond
+	if _, err := ef.Embed(context.Background(), []string{"text"}); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestOpenAIEmbeddingFunctionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("invalid api key"))
+	}))
+	defer server.Close()
+
+	ef := NewOpenAIEmbeddingFunction("bad-key", WithOpenAIBaseURL(server.URL))
+	_, err := ef.Embed(context.Background(), []string{"text"})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		t.Fatalf("Expected HTTPError, got %T", err)
+	}
+	if httpErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, httpErr.StatusCode)
+	}
+}
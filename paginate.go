@@ -0,0 +1,171 @@
+package chromaclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GetAll fetches every record matching req by paging through the collection
+// pageSize rows at a time. Where, WhereDocument, IDs and Include are passed
+// through unchanged; Limit is replaced by pageSize and paging starts at
+// req.Offset, if set.
+func (c *Client) GetAll(ctx context.Context, collectionID string, req GetEmbedding, pageSize int, tenant, database string) (*GetResult, error) {
+	all := &GetResult{}
+	err := c.GetAllFunc(ctx, collectionID, req, pageSize, func(page *GetResult) error {
+		all.IDs = append(all.IDs, page.IDs...)
+		all.Embeddings = append(all.Embeddings, page.Embeddings...)
+		all.Documents = append(all.Documents, page.Documents...)
+		all.Metadatas = append(all.Metadatas, page.Metadatas...)
+		all.Uris = append(all.Uris, page.Uris...)
+		if all.Include == nil {
+			all.Include = page.Include
+		}
+		return nil
+	}, tenant, database)
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// GetAllFunc pages through the records matching req like GetAll, but calls fn
+// with each page instead of accumulating them. Paging stops at the first page
+// shorter than pageSize or when fn returns an error, which is returned as is.
+func (c *Client) GetAllFunc(ctx context.Context, collectionID string, req GetEmbedding, pageSize int, fn func(page *GetResult) error, tenant, database string) error {
+	if pageSize <= 0 {
+		return fmt.Errorf("page size must be positive, got %d", pageSize)
+	}
+
+	offset := 0
+	if req.Offset != nil {
+		offset = *req.Offset
+	}
+
+	for {
+		limit, pageOffset := pageSize, offset
+		req.Limit = &limit
+		req.Offset = &pageOffset
+
+		page, err := c.Get(ctx, collectionID, req, tenant, database)
+		if err != nil {
+			return fmt.Errorf("failed to get page at offset %d: %w", offset, err)
+		}
+		if len(page.IDs) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+		if len(page.IDs) < pageSize {
+			return nil
+		}
+		offset += len(page.IDs)
+	}
+}
+
+// countPageSize is how many IDs CountByFilter fetches per request
+const countPageSize = 1000
+
+// idsOnlyGet is a get request that sends an empty include list, so the
+// server returns only IDs rather than its default documents and metadatas
+type idsOnlyGet struct {
+	IDs           []string               `json:"ids,omitempty"`
+	Where         map[string]interface{} `json:"where,omitempty"`
+	WhereDocument map[string]interface{} `json:"where_document,omitempty"`
+	Limit         int                    `json:"limit"`
+	Offset        int                    `json:"offset"`
+	// Include is not omitempty: an empty list means IDs only
+	Include []Include `json:"include"`
+}
+
+// CountByFilter returns the number of records matching where. Chroma has no
+// filtered count endpoint, so this pages through the matching IDs and counts
+// them client-side; the cost grows with the number of matches, not the size
+// of the collection. A nil where counts every record, like Count.
+func (c *Client) CountByFilter(ctx context.Context, collectionID string, where map[string]interface{}, tenant, database string) (int, error) {
+	if err := c.checkWhere(where); err != nil {
+		return 0, err
+	}
+	return c.countMatching(ctx, "CountByFilter", collectionID, idsOnlyGet{Where: where}, tenant, database)
+}
+
+// countMatching counts the records matching the IDs and filters of req by
+// paging through their IDs countPageSize at a time
+func (c *Client) countMatching(ctx context.Context, op, collectionID string, req idsOnlyGet, tenant, database string) (int, error) {
+	if tenant == "" {
+		tenant = c.tenant
+	}
+	if database == "" {
+		database = c.database
+	}
+
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s/get",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(collectionID))
+	req.Limit, req.Include = countPageSize, []Include{}
+	count := 0
+	for {
+		req.Offset = count
+		var page GetResult
+		if err := c.doRequest(ctx, op, http.MethodPost, path, req, &page); err != nil {
+			return 0, fmt.Errorf("failed to count records at offset %d: %w", count, err)
+		}
+		count += len(page.IDs)
+		if len(page.IDs) < countPageSize {
+			return count, nil
+		}
+	}
+}
+
+// DeleteByFilterPaged deletes the records matching where a page at a time:
+// it gets up to pageSize matching IDs, deletes them by ID and repeats until
+// nothing matches. This bounds each server-side delete, unlike Delete with a
+// Where filter, which removes every match in one operation. progress, if not
+// nil, is called after each page with the running total. The total deleted
+// is returned even on error, so a failed run can simply be retried. A nil
+// where deletes every record.
+func (c *Client) DeleteByFilterPaged(ctx context.Context, collectionID string, where map[string]interface{}, pageSize int, progress func(deleted int), tenant, database string) (int, error) {
+	if pageSize <= 0 {
+		return 0, fmt.Errorf("page size must be positive, got %d", pageSize)
+	}
+	if err := c.checkWhere(where); err != nil {
+		return 0, err
+	}
+	if tenant == "" {
+		tenant = c.tenant
+	}
+	if database == "" {
+		database = c.database
+	}
+
+	path := c.apiPath("/tenants/%s/databases/%s/collections/%s/get",
+		url.PathEscape(tenant), url.PathEscape(database), url.PathEscape(collectionID))
+	deleted := 0
+	var lastFirst string
+	for {
+		// Deleted records no longer match, so every page starts at offset 0
+		req := idsOnlyGet{Where: where, Limit: pageSize, Include: []Include{}}
+		var page GetResult
+		if err := c.doRequest(ctx, "DeleteByFilterPaged", http.MethodPost, path, req, &page); err != nil {
+			return deleted, fmt.Errorf("failed to get records to delete: %w", err)
+		}
+		if len(page.IDs) == 0 {
+			return deleted, nil
+		}
+		if page.IDs[0] == lastFirst {
+			return deleted, fmt.Errorf("record %s still matches after being deleted", lastFirst)
+		}
+		lastFirst = page.IDs[0]
+
+		if err := c.Delete(ctx, collectionID, DeleteEmbedding{IDs: page.IDs}, tenant, database); err != nil {
+			return deleted, fmt.Errorf("failed to delete records: %w", err)
+		}
+		deleted += len(page.IDs)
+		if progress != nil {
+			progress(deleted)
+		}
+		if len(page.IDs) < pageSize {
+			return deleted, nil
+		}
+	}
+}
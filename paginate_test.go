@@ -0,0 +1,181 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newPagingServer serves a collection of total records, recording each
+// request's offset
+func newPagingServer(t *testing.T, total int, offsets *[]int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GetEmbedding
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if req.Where["kind"] != "doc" {
+			t.Errorf("Expected where filter to be preserved, got %v", req.Where)
+		}
+		*offsets = append(*offsets, *req.Offset)
+
+		var result GetResult
+		for i := *req.Offset; i < min(*req.Offset+*req.Limit, total); i++ {
+			result.IDs = append(result.IDs, fmt.Sprintf("id%d", i))
+			result.Documents = append(result.Documents, fmt.Sprintf("doc%d", i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}))
+}
+
+func TestGetAll(t *testing.T) {
+	var offsets []int
+	server := newPagingServer(t, 5, &offsets)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	result, err := client.GetAll(context.Background(), "col-123", GetEmbedding{
+		Where: map[string]interface{}{"kind": "doc"},
+	}, 2, "", "")
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+
+	if len(result.IDs) != 5 || result.IDs[4] != "id4" {
+		t.Errorf("Expected ids id0..id4, got %v", result.IDs)
+	}
+	if len(result.Documents) != 5 || result.Documents[4] != "doc4" {
+		t.Errorf("Expected documents doc0..doc4, got %v", result.Documents)
+	}
+	if fmt.Sprint(offsets) != "[0 2 4]" {
+		t.Errorf("Expected offsets [0 2 4], got %v", offsets)
+	}
+}
+
+func TestGetAllFuncStopsOnError(t *testing.T) {
+	var offsets []int
+	server := newPagingServer(t, 10, &offsets)
+	defer server.Close()
+
+	stop := errors.New("stop")
+	pages := 0
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.GetAllFunc(context.Background(), "col-123", GetEmbedding{
+		Where: map[string]interface{}{"kind": "doc"},
+	}, 3, func(page *GetResult) error {
+		pages++
+		if pages == 2 {
+			return stop
+		}
+		return nil
+	}, "", "")
+	if !errors.Is(err, stop) {
+		t.Fatalf("Expected callback error, got %v", err)
+	}
+	if len(offsets) != 2 {
+		t.Errorf("Expected paging to stop after 2 requests, got %d", len(offsets))
+	}
+}
+
+func TestCountByFilter(t *testing.T) {
+	const total = 2500
+	var offsets []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if string(req["include"]) != "[]" {
+			t.Errorf("Expected an empty include list, got %s", req["include"])
+		}
+		if string(req["where"]) != `{"kind":"doc"}` {
+			t.Errorf("Expected where filter, got %s", req["where"])
+		}
+		var offset, limit int
+		json.Unmarshal(req["offset"], &offset)
+		json.Unmarshal(req["limit"], &limit)
+		offsets = append(offsets, offset)
+
+		var result GetResult
+		for i := offset; i < min(offset+limit, total); i++ {
+			result.IDs = append(result.IDs, fmt.Sprintf("id%d", i))
+		}
+		json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	count, err := client.CountByFilter(context.Background(), "col-123", map[string]interface{}{"kind": "doc"}, "", "")
+	if err != nil {
+		t.Fatalf("CountByFilter() error = %v", err)
+	}
+	if count != total {
+		t.Errorf("Expected %d, got %d", total, count)
+	}
+	if fmt.Sprint(offsets) != "[0 1000 2000]" {
+		t.Errorf("Expected offsets [0 1000 2000], got %v", offsets)
+	}
+}
+
+func TestDeleteByFilterPaged(t *testing.T) {
+	var remaining []string
+	for i := 0; i < 25; i++ {
+		remaining = append(remaining, fmt.Sprintf("id%d", i))
+	}
+	var deletes [][]string
+	stuck := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/get"):
+			var req map[string]json.RawMessage
+			json.NewDecoder(r.Body).Decode(&req)
+			if string(req["where"]) != `{"kind":"doc"}` || string(req["offset"]) != "0" {
+				t.Errorf("Expected where at offset 0, got %s at %s", req["where"], req["offset"])
+			}
+			var limit int
+			json.Unmarshal(req["limit"], &limit)
+			json.NewEncoder(w).Encode(GetResult{IDs: remaining[:min(limit, len(remaining))]})
+		case strings.HasSuffix(r.URL.Path, "/delete"):
+			var req DeleteEmbedding
+			json.NewDecoder(r.Body).Decode(&req)
+			deletes = append(deletes, req.IDs)
+			if !stuck {
+				remaining = remaining[len(req.IDs):]
+			}
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	var reported []int
+	deleted, err := client.DeleteByFilterPaged(context.Background(), "col-123", map[string]interface{}{"kind": "doc"}, 10,
+		func(n int) { reported = append(reported, n) }, "", "")
+	if err != nil {
+		t.Fatalf("DeleteByFilterPaged() error = %v", err)
+	}
+	if deleted != 25 || len(deletes) != 3 || len(deletes[2]) != 5 {
+		t.Errorf("Expected 25 deleted in pages of 10, 10, 5, got %d in %v", deleted, deletes)
+	}
+	if fmt.Sprint(reported) != "[10 20 25]" {
+		t.Errorf("Expected progress [10 20 25], got %v", reported)
+	}
+
+	remaining = []string{"a", "b"}
+	stuck = true
+	deleted, err = client.DeleteByFilterPaged(context.Background(), "col-123", map[string]interface{}{"kind": "doc"}, 2, nil, "", "")
+	if err == nil || !strings.Contains(err.Error(), "still matches") || deleted != 2 {
+		t.Errorf("Expected an error when deleted records keep matching, got %d, %v", deleted, err)
+	}
+
+	if _, err := client.DeleteByFilterPaged(context.Background(), "col-123", nil, 0, nil, "", ""); err == nil {
+		t.Error("Expected an error for a non-positive page size")
+	}
+}
@@ -0,0 +1,90 @@
+package chromaclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// Reranker reorders or filters the hits of one query, e.g. with a
+// cross-encoder. query is the query text, or empty when the query was given
+// as an embedding. The returned hits replace the group as is.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, hits []QueryHit) ([]QueryHit, error)
+}
+
+// RerankerFunc adapts a function to the Reranker interface
+type RerankerFunc func(ctx context.Context, query string, hits []QueryHit) ([]QueryHit, error)
+
+// Rerank calls f
+func (f RerankerFunc) Rerank(ctx context.Context, query string, hits []QueryHit) ([]QueryHit, error) {
+	return f(ctx, query, hits)
+}
+
+// QueryAndRerank runs Query and passes each query's hits through reranker,
+// returning a QueryResult rebuilt from the reranked groups. Columns absent
+// from the original result stay absent. A result whose columns do not line
+// up fails Validate and is returned as an error. A nil reranker returns the
+// query result unchanged.
+func (c *Client) QueryAndRerank(ctx context.Context, collectionID string, req QueryEmbedding, reranker Reranker, tenant, database string) (*QueryResult, error) {
+	result, err := c.Query(ctx, collectionID, req, tenant, database)
+	if err != nil || reranker == nil {
+		return result, err
+	}
+	// Rebuilding the columns from hits would pad a ragged result with zero
+	// values the server never returned
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+
+	groups := result.Groups()
+	for i, hits := range groups {
+		groups[i], err = reranker.Rerank(ctx, at(req.QueryTexts, i), hits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rerank query %d: %w", i, err)
+		}
+	}
+	return result.withGroups(groups), nil
+}
+
+// withGroups returns a copy of r whose columns are rebuilt from groups,
+// keeping only the columns r has
+func (r *QueryResult) withGroups(groups [][]QueryHit) *QueryResult {
+	out := &QueryResult{IDs: make([][]string, len(groups)), Include: r.Include}
+	if len(r.Documents) > 0 {
+		out.Documents = make([][]string, len(groups))
+	}
+	if len(r.Distances) > 0 {
+		out.Distances = make([][]float64, len(groups))
+	}
+	if len(r.Metadatas) > 0 {
+		out.Metadatas = make([][]map[string]interface{}, len(groups))
+	}
+	if len(r.Embeddings) > 0 {
+		out.Embeddings = make([][][]float64, len(groups))
+	}
+	if len(r.Uris) > 0 {
+		out.Uris = make([][]string, len(groups))
+	}
+
+	for i, hits := range groups {
+		for _, hit := range hits {
+			out.IDs[i] = append(out.IDs[i], hit.ID)
+			if out.Documents != nil {
+				out.Documents[i] = append(out.Documents[i], hit.Document)
+			}
+			if out.Distances != nil {
+				out.Distances[i] = append(out.Distances[i], hit.Distance)
+			}
+			if out.Metadatas != nil {
+				out.Metadatas[i] = append(out.Metadatas[i], hit.Metadata)
+			}
+			if out.Embeddings != nil {
+				out.Embeddings[i] = append(out.Embeddings[i], hit.Embedding)
+			}
+			if out.Uris != nil {
+				out.Uris[i] = append(out.Uris[i], hit.URI)
+			}
+		}
+	}
+	return out
+}
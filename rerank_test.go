@@ -0,0 +1,81 @@
+package chromaclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestQueryAndRerank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ids": [["a", "b", "c"], ["d"]], "documents": [["doc-a", "doc-b", "doc-c"], ["doc-d"]],
+			"distances": [[0.1, 0.2, 0.3], [0.4]]}`))
+	}))
+	defer server.Close()
+
+	var queries []string
+	reverse := RerankerFunc(func(ctx context.Context, query string, hits []QueryHit) ([]QueryHit, error) {
+		queries = append(queries, query)
+		hits = slices.Clone(hits)
+		slices.Reverse(hits)
+		return hits[:min(len(hits), 2)], nil
+	})
+
+	client := NewClient(WithBaseURL(server.URL))
+	req := QueryEmbedding{QueryTexts: []string{"first", "second"}, NResults: 3}
+	result, err := client.QueryAndRerank(context.Background(), "col-123", req, reverse, "", "")
+	if err != nil {
+		t.Fatalf("QueryAndRerank() error = %v", err)
+	}
+	if !slices.Equal(queries, []string{"first", "second"}) {
+		t.Errorf("Expected the query texts to be passed, got %v", queries)
+	}
+	if !slices.Equal(result.IDs[0], []string{"c", "b"}) || !slices.Equal(result.Documents[0], []string{"doc-c", "doc-b"}) {
+		t.Errorf("Expected reranked first group c, b, got %v %v", result.IDs[0], result.Documents[0])
+	}
+	if result.Distances[0][0] != 0.3 || result.IDs[1][0] != "d" {
+		t.Errorf("Expected columns to follow the hits, got %+v", result)
+	}
+	if result.Metadatas != nil || result.Embeddings != nil {
+		t.Errorf("Expected absent columns to stay absent, got %+v", result)
+	}
+
+	plain, err := client.QueryAndRerank(context.Background(), "col-123", req, nil, "", "")
+	if err != nil || len(plain.IDs[0]) != 3 {
+		t.Errorf("Expected nil reranker to keep the result, got %v, %v", plain, err)
+	}
+
+	failing := RerankerFunc(func(context.Context, string, []QueryHit) ([]QueryHit, error) {
+		return nil, errors.New("model unavailable")
+	})
+	if _, err := client.QueryAndRerank(context.Background(), "col-123", req, failing, "", ""); err == nil {
+		t.Error("Expected reranker error")
+	}
+}
+
+func TestQueryAndRerankRagged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ids": [["a", "b"], ["c"]], "documents": [["doc-a", "doc-b"], []],
+			"distances": [[0.1, 0.2], [0.3]]}`))
+	}))
+	defer server.Close()
+
+	called := false
+	reranker := RerankerFunc(func(ctx context.Context, query string, hits []QueryHit) ([]QueryHit, error) {
+		called = true
+		return hits, nil
+	})
+	client := NewClient(WithBaseURL(server.URL))
+	req := QueryEmbedding{QueryEmbeddings: [][]float64{{1}, {2}}}
+	_, err := client.QueryAndRerank(context.Background(), "col-123", req, reranker, "", "")
+	if err == nil || !strings.Contains(err.Error(), "group 1: Documents has 0 entries but IDs has 1") {
+		t.Errorf("Expected a ragged result to be rejected, got %v", err)
+	}
+	if called {
+		t.Error("Expected the reranker not to be called")
+	}
+}
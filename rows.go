@@ -0,0 +1,275 @@
+package chromaclient
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Row is a single record from a GetResult. Fields whose column was not
+// included in the result are left at their zero value.
+type Row struct {
+	ID        string
+	Document  string
+	Embedding []float64
+	Metadata  Metadata
+	URI       string
+}
+
+// QueryHit is a single nearest-neighbor match from a QueryResult. Fields whose
+// column was not included in the result are left at their zero value.
+type QueryHit struct {
+	ID        string
+	Document  string
+	Distance  float64
+	Metadata  Metadata
+	Embedding []float64
+	URI       string
+}
+
+// Rows returns the result as one Row per ID
+func (r *GetResult) Rows() []Row {
+	rows := make([]Row, len(r.IDs))
+	for i, id := range r.IDs {
+		rows[i] = Row{
+			ID:        id,
+			Document:  at(r.Documents, i),
+			Embedding: at(r.Embeddings, i),
+			Metadata:  at(r.Metadatas, i),
+			URI:       at(r.Uris, i),
+		}
+	}
+	return rows
+}
+
+// Groups returns the hits for each query embedding or text, in request order
+func (r *QueryResult) Groups() [][]QueryHit {
+	groups := make([][]QueryHit, len(r.IDs))
+	for i := range r.IDs {
+		groups[i] = r.Group(i)
+	}
+	return groups
+}
+
+// Group returns the hits for the i-th query embedding or text. It returns nil
+// if i is out of range.
+func (r *QueryResult) Group(i int) []QueryHit {
+	if i < 0 || i >= len(r.IDs) {
+		return nil
+	}
+
+	ids := r.IDs[i]
+	documents := at(r.Documents, i)
+	distances := at(r.Distances, i)
+	metadatas := at(r.Metadatas, i)
+	embeddings := at(r.Embeddings, i)
+	uris := at(r.Uris, i)
+
+	hits := make([]QueryHit, len(ids))
+	for j, id := range ids {
+		hits[j] = QueryHit{
+			ID:        id,
+			Document:  at(documents, j),
+			Distance:  at(distances, j),
+			Metadata:  at(metadatas, j),
+			Embedding: at(embeddings, j),
+			URI:       at(uris, j),
+		}
+	}
+	return hits
+}
+
+// Validate checks that every included column has one entry per ID, so that
+// Rows and direct indexing cannot silently misalign records. A column that
+// was not included, and so is empty, is accepted.
+func (r *GetResult) Validate() error {
+	return checkResultColumns(len(r.IDs), map[string]int{
+		"Embeddings": len(r.Embeddings),
+		"Documents":  len(r.Documents),
+		"Metadatas":  len(r.Metadatas),
+		"Uris":       len(r.Uris),
+	})
+}
+
+// Validate checks that every included column has one group per query and
+// that each group has one entry per ID in the same group. A column that was
+// not included, and so is empty, is accepted.
+func (r *QueryResult) Validate() error {
+	if err := checkResultColumns(len(r.IDs), map[string]int{
+		"Embeddings": len(r.Embeddings),
+		"Documents":  len(r.Documents),
+		"Metadatas":  len(r.Metadatas),
+		"Distances":  len(r.Distances),
+		"Uris":       len(r.Uris),
+	}); err != nil {
+		return err
+	}
+	for i := range r.IDs {
+		if err := r.checkGroup(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkGroup reports the first column, in a fixed order, that is included
+// but does not have one entry per ID in group i. Unlike the group count, an
+// empty group of an included column is an error.
+func (r *QueryResult) checkGroup(i int) error {
+	ids := len(r.IDs[i])
+	for _, col := range []struct {
+		name    string
+		present bool
+		n       int
+	}{
+		{"Embeddings", len(r.Embeddings) > 0, lenAt(r.Embeddings, i)},
+		{"Documents", len(r.Documents) > 0, lenAt(r.Documents, i)},
+		{"Metadatas", len(r.Metadatas) > 0, lenAt(r.Metadatas, i)},
+		{"Distances", len(r.Distances) > 0, lenAt(r.Distances, i)},
+		{"Uris", len(r.Uris) > 0, lenAt(r.Uris, i)},
+	} {
+		if col.present && col.n != ids {
+			return fmt.Errorf("invalid result: group %d: %s has %d entries but IDs has %d", i, col.name, col.n, ids)
+		}
+	}
+	return nil
+}
+
+// checkResultColumns reports the first non-empty column, in a fixed order,
+// whose length differs from ids
+func checkResultColumns(ids int, columns map[string]int) error {
+	for _, name := range []string{"Embeddings", "Documents", "Metadatas", "Distances", "Uris"} {
+		if n := columns[name]; n != 0 && n != ids {
+			return fmt.Errorf("invalid result: %s has %d entries but IDs has %d", name, n, ids)
+		}
+	}
+	return nil
+}
+
+// lenAt returns len(s[i]), or 0 when i is out of range
+func lenAt[T any](s [][]T, i int) int {
+	return len(at(s, i))
+}
+
+// at returns s[i], or the zero value when i is out of range
+func at[T any](s []T, i int) T {
+	var zero T
+	if i < 0 || i >= len(s) {
+		return zero
+	}
+	return s[i]
+}
+
+// SortByDistance stably sorts the hits of each query in result by ascending
+// distance, moving every column together so rows stay aligned. Use it after
+// filtering or merging results client-side. Groups whose columns do not all
+// have one entry per ID, including groups without distances, are left
+// exactly as they are.
+func SortByDistance(result *QueryResult) {
+	if result == nil {
+		return
+	}
+	for i, ids := range result.IDs {
+		distances := at(result.Distances, i)
+		if len(distances) != len(ids) || result.checkGroup(i) != nil {
+			continue
+		}
+		order := make([]int, len(ids))
+		for j := range order {
+			order[j] = j
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			return cmp.Compare(distances[a], distances[b])
+		})
+
+		result.IDs[i] = permute(result.IDs[i], order)
+		result.Distances[i] = permute(distances, order)
+		if len(result.Documents) > 0 {
+			result.Documents[i] = permute(result.Documents[i], order)
+		}
+		if len(result.Metadatas) > 0 {
+			result.Metadatas[i] = permute(result.Metadatas[i], order)
+		}
+		if len(result.Embeddings) > 0 {
+			result.Embeddings[i] = permute(result.Embeddings[i], order)
+		}
+		if len(result.Uris) > 0 {
+			result.Uris[i] = permute(result.Uris[i], order)
+		}
+	}
+}
+
+// permute returns the entries of s in the given order
+func permute[T any](s []T, order []int) []T {
+	out := make([]T, len(order))
+	for i, j := range order {
+		out[i] = s[j]
+	}
+	return out
+}
+
+// MergeQueryResults merges the results of the same queries run against
+// several collections, e.g. shards of one data set. For each query the hits
+// of all inputs are combined, sorted by ascending distance and cut to the k
+// closest; k <= 0 keeps them all. Only columns present in every input are
+// kept, and hits are left in input order when some input has no distances.
+// Nil and empty inputs are ignored. An input whose columns do not line up
+// fails Validate and is returned as an error, since merging it would
+// produce hits with made-up zero distances.
+func MergeQueryResults(results []*QueryResult, k int) (*QueryResult, error) {
+	var inputs []*QueryResult
+	queries := 0
+	for i, r := range results {
+		if r == nil || len(r.IDs) == 0 {
+			continue
+		}
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("result %d: %w", i, err)
+		}
+		inputs = append(inputs, r)
+		queries = max(queries, len(r.IDs))
+	}
+	if len(inputs) == 0 {
+		return &QueryResult{}, nil
+	}
+
+	// withGroups keeps the columns of its receiver, so start from a copy of
+	// the first input without the columns some other input lacks
+	merged := *inputs[0]
+	for _, r := range inputs[1:] {
+		if len(r.Documents) == 0 {
+			merged.Documents = nil
+		}
+		if len(r.Distances) == 0 {
+			merged.Distances = nil
+		}
+		if len(r.Metadatas) == 0 {
+			merged.Metadatas = nil
+		}
+		if len(r.Embeddings) == 0 {
+			merged.Embeddings = nil
+		}
+		if len(r.Uris) == 0 {
+			merged.Uris = nil
+		}
+		merged.Include = slices.DeleteFunc(slices.Clone(merged.Include), func(inc Include) bool {
+			return !slices.Contains(r.Include, inc)
+		})
+	}
+
+	groups := make([][]QueryHit, queries)
+	for i := range groups {
+		for _, r := range inputs {
+			groups[i] = append(groups[i], r.Group(i)...)
+		}
+		if merged.Distances != nil {
+			slices.SortStableFunc(groups[i], func(a, b QueryHit) int {
+				return cmp.Compare(a.Distance, b.Distance)
+			})
+		}
+		if k > 0 && len(groups[i]) > k {
+			groups[i] = groups[i][:k]
+		}
+	}
+	return merged.withGroups(groups), nil
+}
@@ -0,0 +1,225 @@
+package chromaclient
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGetResultRows(t *testing.T) {
+	result := &GetResult{
+		IDs:       []string{"a", "b"},
+		Documents: []string{"doc-a", "doc-b"},
+		Metadatas: []map[string]interface{}{{"n": 1.0}},
+	}
+
+	rows := result.Rows()
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	if rows[0].ID != "a" || rows[0].Document != "doc-a" || rows[0].Metadata["n"] != 1.0 {
+		t.Errorf("Unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Metadata != nil || rows[1].Embedding != nil || rows[1].URI != "" {
+		t.Errorf("Expected missing columns to be zero, got %+v", rows[1])
+	}
+}
+
+func TestQueryResultGroups(t *testing.T) {
+	result := &QueryResult{
+		IDs:       [][]string{{"a", "b"}, {"c"}},
+		Documents: [][]string{{"doc-a", "doc-b"}, {"doc-c"}},
+		Distances: [][]float64{{0.1, 0.2}},
+	}
+
+	groups := result.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %d", len(groups))
+	}
+	if len(groups[0]) != 2 || groups[0][1].ID != "b" || groups[0][1].Distance != 0.2 {
+		t.Errorf("Unexpected first group: %+v", groups[0])
+	}
+	if len(groups[1]) != 1 || groups[1][0].Document != "doc-c" || groups[1][0].Distance != 0 {
+		t.Errorf("Unexpected second group: %+v", groups[1])
+	}
+}
+
+func TestQueryResultGroup(t *testing.T) {
+	result := &QueryResult{
+		IDs:       [][]string{{"a", "b"}},
+		Documents: [][]string{{"doc-a", "doc-b"}},
+		Metadatas: [][]map[string]interface{}{{{"n": "a"}}},
+	}
+
+	hits := result.Group(0)
+	if len(hits) != 2 {
+		t.Fatalf("Expected 2 hits, got %d", len(hits))
+	}
+	if hits[0].Metadata["n"] != "a" || hits[1].Metadata != nil {
+		t.Errorf("Expected only the first hit to carry metadata, got %+v", hits)
+	}
+	if hits[1].Distance != 0 {
+		t.Errorf("Expected zero distance when distances were not included, got %v", hits[1].Distance)
+	}
+	if got := result.Group(1); got != nil {
+		t.Errorf("Expected nil for out-of-range group, got %+v", got)
+	}
+}
+
+func TestRowsAndGroupURIs(t *testing.T) {
+	get := &GetResult{
+		IDs:  []string{"a", "b"},
+		Uris: []string{"s3://bucket/a.png", "s3://bucket/b.png"},
+	}
+	if rows := get.Rows(); rows[1].URI != "s3://bucket/b.png" {
+		t.Errorf("Expected row URI, got %+v", rows[1])
+	}
+
+	query := &QueryResult{
+		IDs:  [][]string{{"a", "b"}},
+		Uris: [][]string{{"s3://bucket/a.png", "s3://bucket/b.png"}},
+	}
+	if hits := query.Group(0); hits[0].URI != "s3://bucket/a.png" || hits[1].URI != "s3://bucket/b.png" {
+		t.Errorf("Expected hit URIs, got %+v", hits)
+	}
+}
+
+func TestResultValidate(t *testing.T) {
+	get := &GetResult{IDs: []string{"a", "b"}, Documents: []string{"doc-a", "doc-b"}}
+	if err := get.Validate(); err != nil {
+		t.Errorf("Expected consistent GetResult, got %v", err)
+	}
+	get.Metadatas = []map[string]interface{}{{"n": 1.0}}
+	if err := get.Validate(); err == nil || !strings.Contains(err.Error(), "Metadatas has 1 entries but IDs has 2") {
+		t.Errorf("Expected Metadatas mismatch, got %v", err)
+	}
+
+	query := &QueryResult{
+		IDs:       [][]string{{"a", "b"}, {"c"}},
+		Distances: [][]float64{{0.1, 0.2}, {0.3}},
+	}
+	if err := query.Validate(); err != nil {
+		t.Errorf("Expected consistent QueryResult, got %v", err)
+	}
+	query.Documents = [][]string{{"doc-a", "doc-b"}}
+	if err := query.Validate(); err == nil || !strings.Contains(err.Error(), "Documents has 1 entries but IDs has 2") {
+		t.Errorf("Expected group count mismatch, got %v", err)
+	}
+	query.Documents = nil
+	query.Distances[1] = []float64{0.3, 0.4}
+	if err := query.Validate(); err == nil || !strings.Contains(err.Error(), "group 1: Distances has 2 entries but IDs has 1") {
+		t.Errorf("Expected per-group mismatch, got %v", err)
+	}
+	query.Distances[1] = []float64{}
+	if err := query.Validate(); err == nil || !strings.Contains(err.Error(), "group 1: Distances has 0 entries but IDs has 1") {
+		t.Errorf("Expected an empty group of an included column to be rejected, got %v", err)
+	}
+}
+
+func TestSortByDistance(t *testing.T) {
+	result := &QueryResult{
+		IDs:       [][]string{{"a", "b", "c", "d"}, {"e", "f"}},
+		Documents: [][]string{{"doc-a", "doc-b", "doc-c", "doc-d"}, {"doc-e", "doc-f"}},
+		Metadatas: [][]map[string]interface{}{{{"n": "a"}, {"n": "b"}, {"n": "c"}, {"n": "d"}}, {{"n": "e"}, {"n": "f"}}},
+		Distances: [][]float64{{0.3, 0.1, 0.3, 0.2}, {0.5}},
+		Include:   Includes(IncludeDocuments, IncludeMetadatas, IncludeDistances),
+	}
+
+	SortByDistance(result)
+	if want := []string{"b", "d", "a", "c"}; !slices.Equal(result.IDs[0], want) {
+		t.Errorf("Expected stable order %v, got %v", want, result.IDs[0])
+	}
+	if want := []string{"doc-b", "doc-d", "doc-a", "doc-c"}; !slices.Equal(result.Documents[0], want) {
+		t.Errorf("Expected documents to move with IDs, got %v", result.Documents[0])
+	}
+	if want := []float64{0.1, 0.2, 0.3, 0.3}; !slices.Equal(result.Distances[0], want) {
+		t.Errorf("Expected sorted distances %v, got %v", want, result.Distances[0])
+	}
+	if result.Metadatas[0][0]["n"] != "b" || result.Metadatas[0][3]["n"] != "c" {
+		t.Errorf("Expected metadatas to move with IDs, got %v", result.Metadatas[0])
+	}
+	if !slices.Equal(result.IDs[1], []string{"e", "f"}) {
+		t.Errorf("Expected group with missing distances to be unchanged, got %v", result.IDs[1])
+	}
+	if result.Embeddings != nil || len(result.Include) != 3 {
+		t.Errorf("Expected absent columns to stay absent and Include to be kept, got %+v", result)
+	}
+
+	SortByDistance(nil)
+}
+
+func TestSortByDistanceRagged(t *testing.T) {
+	result := &QueryResult{
+		IDs:       [][]string{{"a", "b"}, {"c", "d"}, {"e", "f"}},
+		Documents: [][]string{{"B", "A"}, {"C"}, {"F", "E"}},
+		Distances: [][]float64{{2, 1}, {5, 4}, {6}},
+	}
+
+	SortByDistance(result)
+	if !slices.Equal(result.IDs[0], []string{"b", "a"}) || !slices.Equal(result.Documents[0], []string{"A", "B"}) {
+		t.Errorf("Expected the aligned group to be sorted, got %v %v", result.IDs[0], result.Documents[0])
+	}
+	if !slices.Equal(result.Distances[1], []float64{5, 4}) || !slices.Equal(result.Documents[1], []string{"C"}) {
+		t.Errorf("Expected the group with missing documents to be unchanged, got %v %v", result.Distances[1], result.Documents[1])
+	}
+	if !slices.Equal(result.Distances[2], []float64{6}) || !slices.Equal(result.Documents[2], []string{"F", "E"}) {
+		t.Errorf("Expected the group with missing distances to be unchanged, got %v %v", result.Distances[2], result.Documents[2])
+	}
+}
+
+func TestMergeQueryResults(t *testing.T) {
+	a := &QueryResult{
+		IDs:       [][]string{{"a1", "a2"}, {"a3"}},
+		Documents: [][]string{{"doc-a1", "doc-a2"}, {"doc-a3"}},
+		Metadatas: [][]map[string]interface{}{{{"n": "a1"}, {"n": "a2"}}, {{"n": "a3"}}},
+		Distances: [][]float64{{0.1, 0.4}, {0.2}},
+		Include:   Includes(IncludeDocuments, IncludeMetadatas, IncludeDistances),
+	}
+	b := &QueryResult{
+		IDs:       [][]string{{"b1", "b2"}, {"b3"}},
+		Documents: [][]string{{"doc-b1", "doc-b2"}, {"doc-b3"}},
+		Distances: [][]float64{{0.2, 0.3}, {0.1}},
+		Include:   Includes(IncludeDocuments, IncludeDistances),
+	}
+
+	merged, err := MergeQueryResults([]*QueryResult{a, nil, b}, 3)
+	if err != nil {
+		t.Fatalf("MergeQueryResults() error = %v", err)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Fatalf("Expected a consistent result, got %v", err)
+	}
+	if want := []string{"a1", "b1", "b2"}; !slices.Equal(merged.IDs[0], want) {
+		t.Errorf("Expected top 3 %v, got %v", want, merged.IDs[0])
+	}
+	if want := []string{"doc-a1", "doc-b1", "doc-b2"}; !slices.Equal(merged.Documents[0], want) {
+		t.Errorf("Expected documents to follow IDs, got %v", merged.Documents[0])
+	}
+	if want := []string{"b3", "a3"}; !slices.Equal(merged.IDs[1], want) {
+		t.Errorf("Expected second query %v, got %v", want, merged.IDs[1])
+	}
+	if merged.Metadatas != nil {
+		t.Errorf("Expected metadatas to be dropped when an input lacks them, got %v", merged.Metadatas)
+	}
+	if want := Includes(IncludeDocuments, IncludeDistances); !slices.Equal(merged.Include, want) {
+		t.Errorf("Expected Include %v, got %v", want, merged.Include)
+	}
+	if len(a.Include) != 3 {
+		t.Errorf("Expected inputs to be left unchanged, got %v", a.Include)
+	}
+
+	if all, _ := MergeQueryResults([]*QueryResult{a, b}, 0); len(all.IDs[0]) != 4 {
+		t.Errorf("Expected k <= 0 to keep every hit, got %v", all.IDs[0])
+	}
+	if empty, err := MergeQueryResults(nil, 3); err != nil || len(empty.IDs) != 0 {
+		t.Errorf("Expected an empty result, got %+v, %v", empty, err)
+	}
+
+	ragged := &QueryResult{
+		IDs:       [][]string{{"r1", "r2"}},
+		Distances: [][]float64{{0.5}},
+	}
+	if _, err := MergeQueryResults([]*QueryResult{a, ragged}, 3); err == nil || !strings.Contains(err.Error(), "result 1: invalid result: group 0: Distances has 1 entries but IDs has 2") {
+		t.Errorf("Expected the ragged input to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package chromaclient
+
+import "sort"
+
+// ValueType names a value type in a collection schema
+type ValueType string
+
+const (
+	ValueTypeString       ValueType = "string"
+	ValueTypeInt          ValueType = "int"
+	ValueTypeFloat        ValueType = "float"
+	ValueTypeBool         ValueType = "bool"
+	ValueTypeFloatList    ValueType = "float_list"
+	ValueTypeSparseVector ValueType = "sparse_vector"
+)
+
+// indexNames maps each value type to the index that serves filters and
+// searches on it
+var indexNames = map[ValueType]string{
+	ValueTypeString:       "string_inverted_index",
+	ValueTypeInt:          "int_inverted_index",
+	ValueTypeFloat:        "float_inverted_index",
+	ValueTypeBool:         "bool_inverted_index",
+	ValueTypeFloatList:    "vector_index",
+	ValueTypeSparseVector: "sparse_vector_index",
+}
+
+// SchemaKeys returns the sorted keys that have their own index settings in the
+// collection schema. Keys not listed use the schema defaults.
+func (c *Collection) SchemaKeys() []string {
+	if c.Schema == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(c.Schema.Keys))
+	for k := range c.Schema.Keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsIndexed reports whether values of type vt stored under the metadata key
+// are indexed, so that Where filters on them are fast. Key settings override
+// the schema defaults. A collection without a schema, as returned by servers
+// that predate schemas, indexes all metadata and reports true.
+func (c *Collection) IsIndexed(key string, vt ValueType) bool {
+	if c.Schema == nil {
+		return true
+	}
+	if kt, ok := c.Schema.Keys[key]; ok {
+		if enabled, ok := kt.indexEnabled(vt); ok {
+			return enabled
+		}
+	}
+	enabled, _ := c.Schema.Defaults.indexEnabled(vt)
+	return enabled
+}
+
+// indexEnabled reports whether the index for vt is enabled, and whether vt is
+// configured at all
+func (v ValueTypes) indexEnabled(vt ValueType) (enabled, ok bool) {
+	var cfg map[string]interface{}
+	switch vt {
+	case ValueTypeString:
+		cfg = v.String
+	case ValueTypeInt:
+		cfg = v.Int
+	case ValueTypeFloat:
+		cfg = v.Float
+	case ValueTypeBool:
+		cfg = v.Bool
+	case ValueTypeFloatList:
+		cfg = v.FloatList
+	case ValueTypeSparseVector:
+		cfg = v.SparseVector
+	}
+	index, ok := cfg[indexNames[vt]].(map[string]interface{})
+	if !ok {
+		return false, false
+	}
+	enabled, _ = index["enabled"].(bool)
+	return enabled, true
+}
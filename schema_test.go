@@ -0,0 +1,59 @@
+package chromaclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestCollectionSchema(t *testing.T) {
+	var col Collection
+	err := json.Unmarshal([]byte(`{
+		"id": "col-123",
+		"name": "docs",
+		"schema": {
+			"defaults": {
+				"string": {"string_inverted_index": {"enabled": true, "config": {}}},
+				"int": {"int_inverted_index": {"enabled": true, "config": {}}}
+			},
+			"keys": {
+				"body": {"string": {"string_inverted_index": {"enabled": false, "config": {}}}},
+				"author": {"int": {"int_inverted_index": {"enabled": false, "config": {}}}}
+			}
+		}
+	}`), &col)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal collection: %v", err)
+	}
+
+	if keys := col.SchemaKeys(); fmt.Sprint(keys) != "[author body]" {
+		t.Errorf("Expected keys [author body], got %v", keys)
+	}
+
+	tests := []struct {
+		key  string
+		vt   ValueType
+		want bool
+	}{
+		{"body", ValueTypeString, false},
+		{"author", ValueTypeString, true},
+		{"author", ValueTypeInt, false},
+		{"year", ValueTypeInt, true},
+		{"year", ValueTypeBool, false},
+	}
+	for _, tt := range tests {
+		if got := col.IsIndexed(tt.key, tt.vt); got != tt.want {
+			t.Errorf("IsIndexed(%q, %s) = %v, want %v", tt.key, tt.vt, got, tt.want)
+		}
+	}
+}
+
+func TestCollectionWithoutSchema(t *testing.T) {
+	col := Collection{ID: "col-123"}
+	if keys := col.SchemaKeys(); keys != nil {
+		t.Errorf("Expected no keys, got %v", keys)
+	}
+	if !col.IsIndexed("anything", ValueTypeString) {
+		t.Error("Expected keys to be indexed when there is no schema")
+	}
+}
@@ -0,0 +1,184 @@
+package chromaclient
+
+import "context"
+
+// DatabaseClient is a client scoped to one tenant and database. Its methods
+// mirror the Client methods of the same name without the trailing tenant and
+// database arguments. Create one with Client.Database.
+type DatabaseClient struct {
+	client   *Client
+	tenant   string
+	database string
+}
+
+// Database returns a DatabaseClient for tenant and database. Empty values use
+// the client defaults.
+func (c *Client) Database(tenant, database string) *DatabaseClient {
+	if tenant == "" {
+		tenant = c.tenant
+	}
+	if database == "" {
+		database = c.database
+	}
+	return &DatabaseClient{client: c, tenant: tenant, database: database}
+}
+
+// Tenant returns the tenant of the scope
+func (d *DatabaseClient) Tenant() string {
+	return d.tenant
+}
+
+// Name returns the database of the scope
+func (d *DatabaseClient) Name() string {
+	return d.database
+}
+
+// ListCollections lists all collections in the database
+func (d *DatabaseClient) ListCollections(ctx context.Context) ([]Collection, error) {
+	return d.client.ListCollections(ctx, d.tenant, d.database)
+}
+
+// ListCollectionsPaged lists collections in the database using limit and offset
+func (d *DatabaseClient) ListCollectionsPaged(ctx context.Context, limit, offset int) ([]Collection, error) {
+	return d.client.ListCollectionsPaged(ctx, d.tenant, d.database, limit, offset)
+}
+
+// CountCollections counts the collections in the database
+func (d *DatabaseClient) CountCollections(ctx context.Context) (int, error) {
+	return d.client.CountCollections(ctx, d.tenant, d.database)
+}
+
+// CreateCollection creates a collection in the database
+func (d *DatabaseClient) CreateCollection(ctx context.Context, req CreateCollection) (*Collection, error) {
+	return d.client.CreateCollection(ctx, req, d.tenant, d.database)
+}
+
+// GetOrCreateCollection returns the named collection, creating it with
+// metadata if it does not exist
+func (d *DatabaseClient) GetOrCreateCollection(ctx context.Context, name string, metadata map[string]interface{}) (*Collection, error) {
+	return d.client.GetOrCreateCollection(ctx, name, metadata, d.tenant, d.database)
+}
+
+// CreateCollectionWithSpace gets or creates a collection whose HNSW index uses
+// the given distance space
+func (d *DatabaseClient) CreateCollectionWithSpace(ctx context.Context, name string, space Space) (*Collection, error) {
+	return d.client.CreateCollectionWithSpace(ctx, name, space, d.tenant, d.database)
+}
+
+// CreateCollectionWithSpann gets or creates a collection indexed with SPANN
+func (d *DatabaseClient) CreateCollectionWithSpann(ctx context.Context, name string, cfg SpannConfiguration) (*Collection, error) {
+	return d.client.CreateCollectionWithSpann(ctx, name, cfg, d.tenant, d.database)
+}
+
+// GetCollection gets a collection by name
+func (d *DatabaseClient) GetCollection(ctx context.Context, name string) (*Collection, error) {
+	return d.client.GetCollection(ctx, name, d.tenant, d.database)
+}
+
+// GetCollectionByID gets a collection by ID
+func (d *DatabaseClient) GetCollectionByID(ctx context.Context, id string) (*Collection, error) {
+	return d.client.GetCollectionByID(ctx, id, d.tenant, d.database)
+}
+
+// ResolveCollection gets a collection by ID and checks that it is still named
+// expectedName
+func (d *DatabaseClient) ResolveCollection(ctx context.Context, id, expectedName string) (*Collection, error) {
+	return d.client.ResolveCollection(ctx, id, expectedName, d.tenant, d.database)
+}
+
+// CollectionExists reports whether the named collection exists
+func (d *DatabaseClient) CollectionExists(ctx context.Context, name string) (bool, error) {
+	return d.client.CollectionExists(ctx, name, d.tenant, d.database)
+}
+
+// DeleteCollection deletes a collection by name
+func (d *DatabaseClient) DeleteCollection(ctx context.Context, name string) error {
+	return d.client.DeleteCollection(ctx, name, d.tenant, d.database)
+}
+
+// UpdateCollection updates a collection
+func (d *DatabaseClient) UpdateCollection(ctx context.Context, collectionID string, req UpdateCollection) error {
+	return d.client.UpdateCollection(ctx, collectionID, req, d.tenant, d.database)
+}
+
+// RenameCollection renames a collection and returns it with its new name
+func (d *DatabaseClient) RenameCollection(ctx context.Context, collectionID, newName string) (*Collection, error) {
+	return d.client.RenameCollection(ctx, collectionID, newName, d.tenant, d.database)
+}
+
+// UpdateHNSW changes HNSW settings of an existing collection
+func (d *DatabaseClient) UpdateHNSW(ctx context.Context, collectionID string, cfg HnswConfiguration) (*Collection, error) {
+	return d.client.UpdateHNSW(ctx, collectionID, cfg, d.tenant, d.database)
+}
+
+// SetEmbeddingFunction changes the embedding function recorded in a
+// collection's configuration
+func (d *DatabaseClient) SetEmbeddingFunction(ctx context.Context, collectionID string, cfg EmbeddingFunctionConfiguration) (*Collection, error) {
+	return d.client.SetEmbeddingFunction(ctx, collectionID, cfg, d.tenant, d.database)
+}
+
+// Collection resolves the named collection and returns a handle for it
+func (d *DatabaseClient) Collection(ctx context.Context, name string) (*CollectionHandle, error) {
+	return d.client.Collection(ctx, name, d.tenant, d.database)
+}
+
+// Add adds embeddings to a collection
+func (d *DatabaseClient) Add(ctx context.Context, collectionID string, req AddEmbedding) error {
+	return d.client.Add(ctx, collectionID, req, d.tenant, d.database)
+}
+
+// Update updates embeddings in a collection
+func (d *DatabaseClient) Update(ctx context.Context, collectionID string, req UpdateEmbedding) error {
+	return d.client.Update(ctx, collectionID, req, d.tenant, d.database)
+}
+
+// UpdateMetadataMerge applies metadata patches on top of the current metadata
+// of ids
+func (d *DatabaseClient) UpdateMetadataMerge(ctx context.Context, collectionID string, ids []string, patches []map[string]interface{}) error {
+	return d.client.UpdateMetadataMerge(ctx, collectionID, ids, patches, d.tenant, d.database)
+}
+
+// Upsert upserts embeddings in a collection
+func (d *DatabaseClient) Upsert(ctx context.Context, collectionID string, req AddEmbedding) error {
+	return d.client.Upsert(ctx, collectionID, req, d.tenant, d.database)
+}
+
+// UpsertDocuments upserts a map of ID to document text
+func (d *DatabaseClient) UpsertDocuments(ctx context.Context, collectionID string, docs map[string]string, ef EmbeddingFunction) error {
+	return d.client.UpsertDocuments(ctx, collectionID, docs, ef, d.tenant, d.database)
+}
+
+// Get gets embeddings from a collection
+func (d *DatabaseClient) Get(ctx context.Context, collectionID string, req GetEmbedding) (*GetResult, error) {
+	return d.client.Get(ctx, collectionID, req, d.tenant, d.database)
+}
+
+// Delete deletes embeddings from a collection
+func (d *DatabaseClient) Delete(ctx context.Context, collectionID string, req DeleteEmbedding) error {
+	return d.client.Delete(ctx, collectionID, req, d.tenant, d.database)
+}
+
+// Count returns the number of embeddings in a collection
+func (d *DatabaseClient) Count(ctx context.Context, collectionID string) (int, error) {
+	return d.client.Count(ctx, collectionID, d.tenant, d.database)
+}
+
+// CountByFilter returns the number of records matching where
+func (d *DatabaseClient) CountByFilter(ctx context.Context, collectionID string, where map[string]interface{}) (int, error) {
+	return d.client.CountByFilter(ctx, collectionID, where, d.tenant, d.database)
+}
+
+// DeleteByFilterPaged deletes the records matching where pageSize at a time
+func (d *DatabaseClient) DeleteByFilterPaged(ctx context.Context, collectionID string, where map[string]interface{}, pageSize int, progress func(deleted int)) (int, error) {
+	return d.client.DeleteByFilterPaged(ctx, collectionID, where, pageSize, progress, d.tenant, d.database)
+}
+
+// Snapshot returns every record of a collection keyed by ID
+func (d *DatabaseClient) Snapshot(ctx context.Context, collectionID string) (map[string]Row, error) {
+	return d.client.Snapshot(ctx, collectionID, d.tenant, d.database)
+}
+
+// Query queries a collection for nearest neighbors
+func (d *DatabaseClient) Query(ctx context.Context, collectionID string, req QueryEmbedding) (*QueryResult, error) {
+	return d.client.Query(ctx, collectionID, req, d.tenant, d.database)
+}
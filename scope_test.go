@@ -0,0 +1,68 @@
+package chromaclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDatabaseClient(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/tenants/acme/databases/prod/collections":
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`[{"id": "col-123", "name": "docs"}]`))
+				return
+			}
+			w.Write([]byte(`{"id": "col-123", "name": "docs"}`))
+		case "/api/v2/tenants/acme/databases/prod/collections/col-123/count":
+			w.Write([]byte("2"))
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithTenant("acme"))
+	db := client.Database("", "prod")
+	if db.Tenant() != "acme" || db.Name() != "prod" {
+		t.Errorf("Expected acme/prod, got %s/%s", db.Tenant(), db.Name())
+	}
+
+	ctx := context.Background()
+	if _, err := db.CreateCollection(ctx, CreateCollection{Name: "docs"}); err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+	collections, err := db.ListCollections(ctx)
+	if err != nil {
+		t.Fatalf("ListCollections() error = %v", err)
+	}
+	if len(collections) != 1 {
+		t.Errorf("Expected 1 collection, got %d", len(collections))
+	}
+	if count, err := db.Count(ctx, "col-123"); err != nil || count != 2 {
+		t.Fatalf("Count() = %d, %v", count, err)
+	}
+	if err := db.DeleteCollection(ctx, "docs"); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+
+	want := []string{
+		"POST /api/v2/tenants/acme/databases/prod/collections",
+		"GET /api/v2/tenants/acme/databases/prod/collections",
+		"GET /api/v2/tenants/acme/databases/prod/collections/col-123/count",
+		"DELETE /api/v2/tenants/acme/databases/prod/collections/docs",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("Expected %d requests, got %v", len(want), paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("Expected request %d to be %s, got %s", i, want[i], paths[i])
+		}
+	}
+}
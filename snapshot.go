@@ -0,0 +1,68 @@
+package chromaclient
+
+import (
+	"context"
+	"reflect"
+	"slices"
+)
+
+// snapshotPageSize is how many records Snapshot fetches per request
+const snapshotPageSize = 1000
+
+// Snapshot returns every record of a collection keyed by ID, with its
+// embedding, document, metadata and URI, e.g. to assert a collection's
+// contents in a test regardless of order. Compare two snapshots with
+// DiffSnapshots.
+func (c *Client) Snapshot(ctx context.Context, collectionID string, tenant, database string) (map[string]Row, error) {
+	snapshot := make(map[string]Row)
+	req := GetEmbedding{Include: Includes(IncludeEmbeddings, IncludeDocuments, IncludeMetadatas, IncludeUris)}
+	err := c.GetAllFunc(ctx, collectionID, req, snapshotPageSize, func(page *GetResult) error {
+		for _, row := range page.Rows() {
+			snapshot[row.ID] = row
+		}
+		return nil
+	}, tenant, database)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// SnapshotDiff lists the IDs that differ between two snapshots, each sorted
+type SnapshotDiff struct {
+	// Added are IDs only in the second snapshot
+	Added []string
+	// Removed are IDs only in the first snapshot
+	Removed []string
+	// Changed are IDs in both whose rows differ
+	Changed []string
+}
+
+// Empty reports whether the snapshots were identical
+func (d SnapshotDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffSnapshots compares snapshot a with a later snapshot b. Rows are equal
+// when every column matches exactly.
+func DiffSnapshots(a, b map[string]Row) SnapshotDiff {
+	var diff SnapshotDiff
+	for id, rowA := range a {
+		rowB, ok := b[id]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, id)
+		case !reflect.DeepEqual(rowA, rowB):
+			diff.Changed = append(diff.Changed, id)
+		}
+	}
+	for id := range b {
+		if _, ok := a[id]; !ok {
+			diff.Added = append(diff.Added, id)
+		}
+	}
+	slices.Sort(diff.Added)
+	slices.Sort(diff.Removed)
+	slices.Sort(diff.Changed)
+	return diff
+}
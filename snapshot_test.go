@@ -0,0 +1,61 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GetEmbedding
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Include) != 4 {
+			t.Errorf("Expected every column to be included, got %v", req.Include)
+		}
+		json.NewEncoder(w).Encode(GetResult{
+			IDs:        []string{"b", "a"},
+			Documents:  []string{"doc-b", "doc-a"},
+			Metadatas:  []map[string]interface{}{{"n": 2.0}, {"n": 1.0}},
+			Embeddings: [][]float64{{0.2}, {0.1}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	snapshot, err := client.Snapshot(context.Background(), "col-123", "", "")
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if len(snapshot) != 2 || snapshot["a"].Document != "doc-a" || snapshot["b"].Metadata["n"] != 2.0 {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	before := map[string]Row{
+		"a": {ID: "a", Document: "doc-a"},
+		"b": {ID: "b", Document: "doc-b", Metadata: Metadata{"n": 1.0}},
+		"c": {ID: "c", Embedding: []float64{0.1}},
+	}
+	after := map[string]Row{
+		"a": {ID: "a", Document: "doc-a"},
+		"b": {ID: "b", Document: "doc-b", Metadata: Metadata{"n": 2.0}},
+		"d": {ID: "d", Document: "doc-d"},
+		"e": {ID: "e", Document: "doc-e"},
+	}
+
+	diff := DiffSnapshots(before, after)
+	if !slices.Equal(diff.Added, []string{"d", "e"}) || !slices.Equal(diff.Removed, []string{"c"}) || !slices.Equal(diff.Changed, []string{"b"}) {
+		t.Errorf("Unexpected diff: %+v", diff)
+	}
+	if diff.Empty() {
+		t.Error("Expected a non-empty diff")
+	}
+	if diff := DiffSnapshots(before, before); !diff.Empty() {
+		t.Errorf("Expected no differences, got %+v", diff)
+	}
+}
@@ -0,0 +1,24 @@
+package chromaclient
+
+import (
+	"net/http"
+	"strings"
+)
+
+// spanStarter starts a trace span for a request and returns the request to
+// send, carrying the span context, and a function that ends the span. It is
+// set by WithTracerProvider, which is only available in builds with the otel
+// tag; when nil, doRequest does no tracing work.
+type spanStarter func(req *http.Request, op string) (*http.Request, func(status int, err error))
+
+// collectionFromPath returns the collection segment of an API path and
+// whether it is an ID (document operations) rather than a name
+func collectionFromPath(path string) (collection string, isID bool) {
+	_, rest, ok := strings.Cut(path, "/collections/")
+	if !ok {
+		return "", false
+	}
+	collection, action, _ := strings.Cut(rest, "/")
+	collection, _, _ = strings.Cut(collection, "?")
+	return collection, action != ""
+}
@@ -0,0 +1,53 @@
+//go:build otel
+
+package chromaclient
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/kevensen/go-chroma-client"
+
+// WithTracerProvider records an OpenTelemetry span named "chroma.<Method>"
+// around each request and propagates the trace context in the request
+// headers using the global propagator. It is only available in builds with
+// the otel tag.
+func WithTracerProvider(tp trace.TracerProvider) ClientOption {
+	return func(c *Client) {
+		tracer := tp.Tracer(tracerName)
+		c.startSpan = func(req *http.Request, op string) (*http.Request, func(status int, err error)) {
+			attrs := []attribute.KeyValue{
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.path", req.URL.Path),
+			}
+			if collection, isID := collectionFromPath(req.URL.Path); isID {
+				attrs = append(attrs, attribute.String("chroma.collection.id", collection))
+			} else if collection != "" {
+				attrs = append(attrs, attribute.String("chroma.collection.name", collection))
+			}
+
+			ctx, span := tracer.Start(req.Context(), "chroma."+op,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...))
+			req = req.WithContext(ctx)
+			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			return req, func(status int, err error) {
+				if status != 0 {
+					span.SetAttributes(attribute.Int("http.response.status_code", status))
+				}
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+				}
+				span.End()
+			}
+		}
+	}
+}
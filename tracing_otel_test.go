@@ -0,0 +1,65 @@
+//go:build otel
+
+package chromaclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracerProvider(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithTracerProvider(tp))
+	_, err := client.Query(context.Background(), "col-123", QueryEmbedding{
+		QueryEmbeddings: [][]float64{{0.1}},
+	}, "", "")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 ended span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "chroma.Query" {
+		t.Errorf("Expected span chroma.Query, got %s", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("Expected error status, got %v", span.Status())
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["chroma.collection.id"].AsString() != "col-123" {
+		t.Errorf("Expected collection id attribute, got %v", attrs)
+	}
+	if attrs["http.response.status_code"].AsInt64() != 500 {
+		t.Errorf("Expected status code attribute 500, got %v", attrs)
+	}
+
+	if traceparent == "" {
+		t.Error("Expected traceparent header to be propagated")
+	}
+}
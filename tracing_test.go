@@ -0,0 +1,23 @@
+package chromaclient
+
+import "testing"
+
+func TestCollectionFromPath(t *testing.T) {
+	tests := []struct {
+		path       string
+		collection string
+		isID       bool
+	}{
+		{"/api/v2/tenants/t/databases/d/collections/col-123/query", "col-123", true},
+		{"/api/v2/tenants/t/databases/d/collections/docs", "docs", false},
+		{"/api/v2/tenants/t/databases/d/collections", "", false},
+		{"/api/v2/heartbeat", "", false},
+	}
+
+	for _, tt := range tests {
+		collection, isID := collectionFromPath(tt.path)
+		if collection != tt.collection || isID != tt.isID {
+			t.Errorf("collectionFromPath(%q) = %q, %v; want %q, %v", tt.path, collection, isID, tt.collection, tt.isID)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package chromaclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeClientCert generates a self-signed client certificate, writes it and
+// its key as PEM files in dir, and returns the paths and the certificate
+func writeClientCert(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "chroma-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile, cert
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := writeClientCert(t, dir)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"1.0.0"`))
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.crt")
+	writePEM(t, caFile, "CERTIFICATE", server.Certificate().Raw)
+
+	client := NewClient(WithBaseURL(server.URL), WithRootCAs(caFile), WithClientCert(certFile, keyFile))
+	if err := client.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if client.timeout != 30*time.Second {
+		t.Errorf("Expected default timeout to be kept, got %v", client.timeout)
+	}
+	version, err := client.Version(context.Background())
+	if err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if version != "1.0.0" {
+		t.Errorf("Expected version 1.0.0, got %s", version)
+	}
+
+	// Without the client certificate the handshake is rejected
+	client = NewClient(WithBaseURL(server.URL), WithRootCAs(caFile))
+	if _, err := client.Version(context.Background()); err == nil {
+		t.Error("Expected handshake error without a client certificate")
+	}
+}
+
+func TestTLSOptionErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opt  ClientOption
+		want string
+	}{
+		{"missing cert", WithClientCert(filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key")), "failed to load client certificate"},
+		{"missing CA file", WithRootCAs(filepath.Join(dir, "missing.pem")), "failed to read root CAs"},
+		{"no certificates", WithRootCAs(notPEM), "no certificates found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(tt.opt)
+			if err := client.Err(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Expected Err() containing %q, got %v", tt.want, err)
+			}
+			if _, err := client.Version(context.Background()); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected requests to fail with %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestTLSOptionsWithCustomHTTPClient(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeClientCert(t, dir)
+
+	client := NewClient(WithHTTPClient(&http.Client{}), WithClientCert(certFile, keyFile))
+	if err := client.Err(); err == nil || !strings.Contains(err.Error(), "WithHTTPClient") {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+}
+
+func TestInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"1.0.0"`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if _, err := client.Version(context.Background()); err == nil {
+		t.Fatal("Expected certificate error without WithInsecureSkipVerify")
+	}
+
+	var buf bytes.Buffer
+	client = NewClient(
+		WithBaseURL(server.URL),
+		WithInsecureSkipVerify(true),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+	if _, err := client.Version(context.Background()); err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "verification is disabled") {
+		t.Errorf("Expected a warning to be logged, got: %s", buf.String())
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "chroma.invalid:8000" {
+			t.Errorf("Expected proxied request for chroma.invalid:8000, got %s", r.URL.Host)
+		}
+		if r.URL.Path != "/api/v2/version" {
+			t.Errorf("Expected path /api/v2/version, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`"1.0.0"`))
+	}))
+	defer proxy.Close()
+
+	client := NewClient(WithBaseURL("http://chroma.invalid:8000"), WithProxy(proxy.URL))
+	if err := client.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	version, err := client.Version(context.Background())
+	if err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if version != "1.0.0" {
+		t.Errorf("Expected version 1.0.0, got %s", version)
+	}
+}
+
+func TestWithProxyInvalid(t *testing.T) {
+	for _, proxyURL := range []string{"://bad", "proxy.corp:3128", "ftp://proxy.corp"} {
+		client := NewClient(WithProxy(proxyURL))
+		if err := client.Err(); err == nil || !strings.Contains(err.Error(), "invalid proxy URL") {
+			t.Errorf("WithProxy(%q): expected invalid proxy error, got %v", proxyURL, err)
+		}
+	}
+}
+
+func TestWithTransportOptions(t *testing.T) {
+	client := NewClient(WithTransportOptions(200, 50, time.Minute))
+	if err := client.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if client.httpClient.Transport != client.transport {
+		t.Fatal("Expected the built-in transport to be used")
+	}
+	if client.transport.MaxIdleConns != 200 || client.transport.MaxIdleConnsPerHost != 50 || client.transport.IdleConnTimeout != time.Minute {
+		t.Errorf("Expected 200/50/1m, got %d/%d/%v",
+			client.transport.MaxIdleConns, client.transport.MaxIdleConnsPerHost, client.transport.IdleConnTimeout)
+	}
+
+	defaults := http.DefaultTransport.(*http.Transport)
+	client = NewClient(WithTransportOptions(0, 16, 0))
+	if client.transport.MaxIdleConns != defaults.MaxIdleConns || client.transport.IdleConnTimeout != defaults.IdleConnTimeout {
+		t.Errorf("Expected zero values to keep the defaults, got %d/%v", client.transport.MaxIdleConns, client.transport.IdleConnTimeout)
+	}
+
+	client = NewClient(WithTransportOptions(-1, 0, 0))
+	if err := client.Err(); err == nil || !strings.Contains(err.Error(), "invalid transport options") {
+		t.Errorf("Expected invalid transport options error, got %v", err)
+	}
+
+	client = NewClient(WithHTTPClient(&http.Client{}), WithTransportOptions(10, 10, 0))
+	if err := client.Err(); err == nil || !strings.Contains(err.Error(), "WithHTTPClient") {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+}
+
+func TestWithHTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"1.0.0"`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	for _, tt := range []struct {
+		enabled bool
+		want    string
+	}{
+		{true, "HTTP/2.0"},
+		{false, "HTTP/1.1"},
+	} {
+		var proto string
+		client := NewClient(
+			WithBaseURL(server.URL),
+			WithInsecureSkipVerify(true),
+			WithHTTP2(tt.enabled),
+			WithResponseInspector(func(resp *http.Response) { proto = resp.Proto }),
+		)
+		if _, err := client.Version(context.Background()); err != nil {
+			t.Fatalf("WithHTTP2(%v): Version() error = %v", tt.enabled, err)
+		}
+		if proto != tt.want {
+			t.Errorf("WithHTTP2(%v): expected %s, got %s", tt.enabled, tt.want, proto)
+		}
+	}
+}
+
+func TestClose(t *testing.T) {
+	closed := make(chan struct{}, 1)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"1.0.0"`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			closed <- struct{}{}
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	if _, err := client.Version(context.Background()); err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Close to close the idle connection")
+	}
+	if _, err := client.Version(context.Background()); err != nil {
+		t.Errorf("Expected the client to be usable after Close, got %v", err)
+	}
+
+	custom := NewClient(WithHTTPClient(&http.Client{}))
+	if err := custom.Close(); err != nil {
+		t.Errorf("Close() on a custom client error = %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// TypedCollection wraps a CollectionHandle so metadata is read and written as
+// a struct T instead of a map. T is converted through encoding/json, so its
+// json tags name the metadata keys that Where filters match on, and its
+// fields must encode to strings, numbers, booleans or null.
+type TypedCollection[T any] struct {
+	*CollectionHandle
+}
+
+// NewTypedCollection wraps h for metadata of type T
+func NewTypedCollection[T any](h *CollectionHandle) *TypedCollection[T] {
+	return &TypedCollection[T]{CollectionHandle: h}
+}
+
+// AddTyped adds records whose metadata is given as values of T. documents,
+// embeddings and metas follow the same rules as the columns of AddEmbedding.
+func (c *TypedCollection[T]) AddTyped(ctx context.Context, ids, documents []string, embeddings [][]float64, metas []T) error {
+	metadatas, err := toMetadatas(metas)
+	if err != nil {
+		return err
+	}
+	return c.Add(ctx, AddEmbedding{
+		IDs:        ids,
+		Documents:  documents,
+		Embeddings: embeddings,
+		Metadatas:  metadatas,
+	})
+}
+
+// GetTyped gets records like Get and also decodes each record's metadata into
+// a T, aligned with result.IDs. Records without metadata decode to the zero
+// value. Metadatas are added to req.Include, or to the client's default
+// Include, if it names other columns only.
+func (c *TypedCollection[T]) GetTyped(ctx context.Context, req GetEmbedding) (*GetResult, []T, error) {
+	if len(req.Include) == 0 {
+		req.Include = c.client.defaultGetInclude()
+	}
+	if len(req.Include) > 0 && !slices.Contains(req.Include, IncludeMetadatas) {
+		req.Include = append(slices.Clone(req.Include), IncludeMetadatas)
+	}
+	result, err := c.Get(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	metas := make([]T, len(result.IDs))
+	for i, id := range result.IDs {
+		if err := fromMetadata(at(result.Metadatas, i), &metas[i]); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode metadata of %s: %w", id, err)
+		}
+	}
+	return result, metas, nil
+}
+
+// toMetadatas converts each value to a metadata map through its JSON encoding
+func toMetadatas[T any](metas []T) ([]map[string]interface{}, error) {
+	if len(metas) == 0 {
+		return nil, nil
+	}
+	out := make([]map[string]interface{}, len(metas))
+	for i, m := range metas {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return nil, fmt.Errorf("invalid records: metadata %d: %w", i, err)
+		}
+		if err := json.Unmarshal(data, &out[i]); err != nil {
+			return nil, fmt.Errorf("invalid records: metadata %d must encode to a JSON object: %w", i, err)
+		}
+	}
+	return out, nil
+}
+
+// fromMetadata decodes a metadata map into v through its JSON encoding
+func fromMetadata(metadata map[string]interface{}, v interface{}) error {
+	if metadata == nil {
+		return nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
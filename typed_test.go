@@ -0,0 +1,87 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type docMeta struct {
+	Category string `json:"category"`
+	Index    int    `json:"index"`
+}
+
+func TestTypedCollection(t *testing.T) {
+	var stored []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/collections/docs"):
+			w.Write([]byte(`{"id": "col-123", "name": "docs"}`))
+		case strings.HasSuffix(r.URL.Path, "/add"):
+			var req AddEmbedding
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("Failed to decode request body: %v", err)
+			}
+			stored = req.Metadatas
+			w.Write([]byte(`{}`))
+		case strings.HasSuffix(r.URL.Path, "/get"):
+			var req GetEmbedding
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("Failed to decode request body: %v", err)
+			}
+			if len(req.Include) != 2 || req.Include[1] != IncludeMetadatas {
+				t.Errorf("Expected metadatas to be added to include, got %v", req.Include)
+			}
+			json.NewEncoder(w).Encode(GetResult{
+				IDs:       []string{"a", "b", "c"},
+				Metadatas: append(stored, nil),
+			})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+	handle, err := client.Collection(ctx, "docs", "", "")
+	if err != nil {
+		t.Fatalf("Collection() error = %v", err)
+	}
+	col := NewTypedCollection[docMeta](handle)
+
+	err = col.AddTyped(ctx, []string{"a", "b"}, []string{"doc-a", "doc-b"}, nil, []docMeta{
+		{Category: "tech", Index: 1},
+		{Category: "news", Index: 2},
+	})
+	if err != nil {
+		t.Fatalf("AddTyped() error = %v", err)
+	}
+	if stored[0]["category"] != "tech" || stored[1]["index"] != 2.0 {
+		t.Errorf("Expected metadata keys from json tags, got %v", stored)
+	}
+
+	result, metas, err := col.GetTyped(ctx, GetEmbedding{Include: Includes(IncludeDocuments)})
+	if err != nil {
+		t.Fatalf("GetTyped() error = %v", err)
+	}
+	if len(metas) != len(result.IDs) {
+		t.Fatalf("Expected one metadata per ID, got %d for %d", len(metas), len(result.IDs))
+	}
+	if metas[1] != (docMeta{Category: "news", Index: 2}) {
+		t.Errorf("Expected news/2, got %+v", metas[1])
+	}
+	if metas[2] != (docMeta{}) {
+		t.Errorf("Expected zero value for missing metadata, got %+v", metas[2])
+	}
+}
+
+func TestTypedCollectionRejectsNonObjects(t *testing.T) {
+	client := NewClient(WithBaseURL("http://127.0.0.1:0"))
+	col := NewTypedCollection[string](&CollectionHandle{client: client, id: "col-123"})
+	err := col.AddTyped(context.Background(), []string{"a"}, []string{"doc"}, nil, []string{"not an object"})
+	if err == nil || !strings.Contains(err.Error(), "must encode to a JSON object") {
+		t.Errorf("Expected JSON object error, got %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package chromaclient
 
-import "time"
+import (
+	"errors"
+	"net/http"
+	"time"
+)
 
 // Space represents the vector space for similarity calculation
 type Space string
@@ -11,6 +15,15 @@ const (
 	SpaceIP     Space = "ip"
 )
 
+// Valid reports whether s is one of the supported spaces
+func (s Space) Valid() bool {
+	switch s {
+	case SpaceL2, SpaceCosine, SpaceIP:
+		return true
+	}
+	return false
+}
+
 // Include represents what to include in query/get results
 type Include string
 
@@ -22,6 +35,11 @@ const (
 	IncludeUris       Include = "uris"
 )
 
+// Includes builds an Include list for a query or get request
+func Includes(includes ...Include) []Include {
+	return includes
+}
+
 // HnswConfiguration represents HNSW index configuration
 type HnswConfiguration struct {
 	EfConstruction *int     `json:"ef_construction,omitempty"`
@@ -113,7 +131,7 @@ type Collection struct {
 	LogPosition       int64                   `json:"log_position"`
 	Version           int32                   `json:"version"`
 	Dimension         *int32                  `json:"dimension,omitempty"`
-	Metadata          map[string]interface{}  `json:"metadata,omitempty"`
+	Metadata          Metadata                `json:"metadata,omitempty"`
 	Schema            *InternalSchema         `json:"schema,omitempty"`
 }
 
@@ -171,7 +189,8 @@ type DeleteEmbedding struct {
 
 // QueryEmbedding is the request body for querying embeddings
 type QueryEmbedding struct {
-	QueryEmbeddings [][]float64            `json:"query_embeddings"`
+	QueryEmbeddings [][]float64            `json:"query_embeddings,omitempty"`
+	QueryTexts      []string               `json:"query_texts,omitempty"`
 	NResults        int                    `json:"n_results,omitempty"`
 	Where           map[string]interface{} `json:"where,omitempty"`
 	WhereDocument   map[string]interface{} `json:"where_document,omitempty"`
@@ -203,11 +222,33 @@ type QueryResult struct {
 // PreflightChecks represents the preflight check response
 type PreflightChecks map[string]interface{}
 
+// ServerCapabilities is the parsed form of the pre-flight checks. Fields the
+// server did not report are left at their zero value.
+type ServerCapabilities struct {
+	MaxBatchSize           int
+	SupportsBase64Encoding bool
+	// Raw holds every reported key, including ones not parsed above
+	Raw PreflightChecks
+}
+
 // HeartbeatResponse represents the heartbeat response
 type HeartbeatResponse struct {
 	NanosecondHeartbeat int64 `json:"nanosecond heartbeat"`
 }
 
+// ServerStatus is the combined result of Status. Healthy reports whether the
+// server answered its heartbeat.
+type ServerStatus struct {
+	Healthy             bool
+	Version             string
+	NanosecondHeartbeat int64
+}
+
+// RootInfo represents the root endpoint response
+type RootInfo struct {
+	NanosecondHeartbeat int64 `json:"nanosecond heartbeat"`
+}
+
 // DefaultTenant is the default tenant name
 const DefaultTenant = "default_tenant"
 
@@ -219,8 +260,23 @@ type HTTPError struct {
 	StatusCode int
 	Message    string
 	Timestamp  time.Time
+	// RequestID is the X-Request-ID sent with the request, if
+	// WithRequestIDGenerator is set
+	RequestID string
 }
 
 func (e *HTTPError) Error() string {
 	return e.Message
 }
+
+// ErrConflict matches an HTTPError with status 409 Conflict, which the server
+// returns when creating a collection that already exists without GetOrCreate:
+//
+//	if errors.Is(err, chromaclient.ErrConflict) { ... }
+var ErrConflict = errors.New("chroma resource already exists")
+
+// Is reports whether e matches target, so that errors.Is can test an
+// HTTPError against ErrConflict
+func (e *HTTPError) Is(target error) bool {
+	return target == ErrConflict && e.StatusCode == http.StatusConflict
+}
@@ -0,0 +1,64 @@
+package chromaclient
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIncludesMarshalJSON(t *testing.T) {
+	req := QueryEmbedding{
+		QueryEmbeddings: [][]float64{{0.1}},
+		Include: Includes(
+			IncludeDistances,
+			IncludeDocuments,
+			IncludeEmbeddings,
+			IncludeMetadatas,
+			IncludeUris,
+		),
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+
+	var decoded struct {
+		Include []string `json:"include"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal request: %v", err)
+	}
+
+	want := []string{"distances", "documents", "embeddings", "metadatas", "uris"}
+	if len(decoded.Include) != len(want) {
+		t.Fatalf("Expected %d include values, got %d", len(want), len(decoded.Include))
+	}
+	for i, v := range want {
+		if decoded.Include[i] != v {
+			t.Errorf("Expected include[%d] to be %s, got %s", i, v, decoded.Include[i])
+		}
+	}
+}
+
+func TestIncludesEmpty(t *testing.T) {
+	data, err := json.Marshal(GetEmbedding{Include: Includes()})
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	if string(data) != "{}" {
+		t.Errorf("Expected empty include to be omitted, got %s", data)
+	}
+}
+
+func TestSpaceValid(t *testing.T) {
+	for _, s := range []Space{SpaceL2, SpaceCosine, SpaceIP} {
+		if !s.Valid() {
+			t.Errorf("Expected %q to be valid", s)
+		}
+	}
+	for _, s := range []Space{"", "cosign", "L2"} {
+		if s.Valid() {
+			t.Errorf("Expected %q to be invalid", s)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package chromaclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// validateAddEmbedding checks an add or upsert payload before it is sent.
+// Every row must carry an embedding or a document, and each optional column
+// that is present must have one entry per ID.
+func validateAddEmbedding(req AddEmbedding) error {
+	return validateRecords(len(req.IDs), len(req.Embeddings), len(req.Documents), len(req.Metadatas), len(req.Uris))
+}
+
+// validate and recordIDs let writeRecords handle AddEmbedding
+func (req AddEmbedding) validate() error {
+	return validateAddEmbedding(req)
+}
+
+func (req AddEmbedding) recordIDs() []string {
+	return req.IDs
+}
+
+// validateRecords applies the add and upsert checks to column lengths, so
+// payloads with float32 and float64 embeddings are validated alike
+func validateRecords(ids, embeddings, documents, metadatas, uris int) error {
+	if embeddings == 0 && documents == 0 {
+		return fmt.Errorf("invalid records: one of Embeddings or Documents is required")
+	}
+	if err := checkColumnLength("Embeddings", embeddings, ids); err != nil {
+		return err
+	}
+	if err := checkColumnLength("Documents", documents, ids); err != nil {
+		return err
+	}
+	if err := checkColumnLength("Metadatas", metadatas, ids); err != nil {
+		return err
+	}
+	return checkColumnLength("Uris", uris, ids)
+}
+
+// checkColumnLength reports an error if an optional column is present but
+// does not have exactly one entry per ID
+func checkColumnLength(name string, n, ids int) error {
+	if n != 0 && n != ids {
+		return fmt.Errorf("invalid records: %s has %d entries but IDs has %d", name, n, ids)
+	}
+	return nil
+}
+
+// DuplicateIDPolicy controls what Add does with an ID that appears more
+// than once in one request
+type DuplicateIDPolicy int
+
+const (
+	// RejectDuplicateIDs fails the add before anything is sent, listing each
+	// duplicate ID with its positions. It is the default.
+	RejectDuplicateIDs DuplicateIDPolicy = iota
+	// KeepLastDuplicateID keeps only the last row of each duplicate ID
+	KeepLastDuplicateID
+)
+
+// WithDuplicateIDPolicy sets how Add, AddBatched, AddConcurrent and Add32
+// handle duplicate IDs within one request
+func WithDuplicateIDPolicy(policy DuplicateIDPolicy) ClientOption {
+	return func(c *Client) {
+		c.duplicateIDs = policy
+	}
+}
+
+// resolveDuplicateIDs applies the client's DuplicateIDPolicy to req
+func resolveDuplicateIDs[R records[R]](c *Client, req R) (R, error) {
+	if c.duplicateIDs == KeepLastDuplicateID {
+		return req.dedupKeepLast(), nil
+	}
+	return req, checkDuplicateIDs(req.recordIDs())
+}
+
+// checkDuplicateIDs reports every ID that appears more than once, with its
+// positions, in order of first appearance
+func checkDuplicateIDs(ids []string) error {
+	positions := make(map[string][]int, len(ids))
+	var duplicates []string
+	for i, id := range ids {
+		positions[id] = append(positions[id], i)
+		if len(positions[id]) == 2 {
+			duplicates = append(duplicates, id)
+		}
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	parts := make([]string, len(duplicates))
+	for i, id := range duplicates {
+		at := make([]string, len(positions[id]))
+		for j, pos := range positions[id] {
+			at[j] = strconv.Itoa(pos)
+		}
+		parts[i] = fmt.Sprintf("%q at %s", id, strings.Join(at, ", "))
+	}
+	return fmt.Errorf("invalid records: duplicate IDs %s", strings.Join(parts, "; "))
+}
+
+// validateQuery checks that a query carries either embeddings or texts
+func validateQuery(req QueryEmbedding) error {
+	hasEmbeddings := len(req.QueryEmbeddings) > 0
+	hasTexts := len(req.QueryTexts) > 0
+	switch {
+	case hasEmbeddings && hasTexts:
+		return fmt.Errorf("invalid query: set either QueryEmbeddings or QueryTexts, not both")
+	case !hasEmbeddings && !hasTexts:
+		return fmt.Errorf("invalid query: one of QueryEmbeddings or QueryTexts is required")
+	}
+	return nil
+}
+
+// checkDimensions reports the first embedding whose length differs from dim.
+// what names the embeddings in the error, e.g. "embedding" or "query
+// embedding".
+func checkDimensions(what string, embeddings [][]float64, dim int) error {
+	for i, e := range embeddings {
+		if len(e) != dim {
+			return fmt.Errorf("%s %d has dimension %d, collection expects %d", what, i, len(e), dim)
+		}
+	}
+	return nil
+}
+
+// validateConfiguration checks the spaces of a collection configuration. A nil
+// configuration or space leaves the choice to the server.
+func validateConfiguration(cfg *CollectionConfiguration) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Hnsw != nil {
+		if err := checkSpace("hnsw", cfg.Hnsw.Space); err != nil {
+			return err
+		}
+	}
+	if cfg.Spann != nil {
+		return checkSpace("spann", cfg.Spann.Space)
+	}
+	return nil
+}
+
+// checkSpace reports an error if space is set to an unsupported value
+func checkSpace(index string, space *Space) error {
+	if space != nil && !space.Valid() {
+		return fmt.Errorf("invalid configuration: %s space %q must be one of l2, cosine or ip", index, *space)
+	}
+	return nil
+}
+
+// WithWhereValidation makes Get, Get32, Query, Delete and the filtered count
+// and delete helpers check their Where filters with ValidateWhere before
+// sending. It is off by default, leaving
+// filters entirely to the server.
+func WithWhereValidation(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.validateWhere = enabled
+	}
+}
+
+// ValidateWhere checks that the operators of a metadata filter have operands
+// of the right type: $and and $or take a list of filters, $in and $nin a
+// list of values, $gt, $gte, $lt and $lte a number, and $eq, $ne and plain
+// equality a string, number or bool. Unknown operators are not checked.
+func ValidateWhere(where map[string]interface{}) error {
+	if err := checkWhereFilter(where); err != nil {
+		return fmt.Errorf("invalid where: %w", err)
+	}
+	return nil
+}
+
+// checkWhere validates where when WithWhereValidation is enabled
+func (c *Client) checkWhere(where map[string]interface{}) error {
+	if !c.validateWhere || where == nil {
+		return nil
+	}
+	return ValidateWhere(where)
+}
+
+func checkWhereFilter(where map[string]interface{}) error {
+	for key, cond := range where {
+		switch key {
+		case "$and", "$or":
+			operands := reflect.ValueOf(cond)
+			if operands.Kind() != reflect.Slice {
+				return fmt.Errorf("%s requires a list of filters, got %T", key, cond)
+			}
+			for i := 0; i < operands.Len(); i++ {
+				sub, ok := asFilter(operands.Index(i).Interface())
+				if !ok {
+					return fmt.Errorf("%s operand %d must be a filter, got %T", key, i, operands.Index(i).Interface())
+				}
+				if err := checkWhereFilter(sub); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		ops, ok := asFilter(cond)
+		if !ok {
+			if !isScalar(cond) {
+				return fmt.Errorf("%q must be compared to a string, number or bool, got %T", key, cond)
+			}
+			continue
+		}
+		for op, operand := range ops {
+			if err := checkOperand(key, op, operand); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkOperand checks the operand of one comparison on key
+func checkOperand(key, op string, operand interface{}) error {
+	switch op {
+	case "$eq", "$ne":
+		if !isScalar(operand) {
+			return fmt.Errorf("%s on %q requires a string, number or bool, got %T", op, key, operand)
+		}
+	case "$gt", "$gte", "$lt", "$lte":
+		if !isNumber(operand) {
+			return fmt.Errorf("%s on %q requires a number, got %T", op, key, operand)
+		}
+	case "$in", "$nin":
+		values := reflect.ValueOf(operand)
+		if values.Kind() != reflect.Slice {
+			return fmt.Errorf("%s on %q requires a list, got %T", op, key, operand)
+		}
+		for i := 0; i < values.Len(); i++ {
+			if v := values.Index(i).Interface(); !isScalar(v) {
+				return fmt.Errorf("%s on %q: value %d must be a string, number or bool, got %T", op, key, i, v)
+			}
+		}
+	}
+	return nil
+}
+
+// asFilter returns v as a filter map, accepting named map types such as
+// where.Filter
+func asFilter(v interface{}) (map[string]interface{}, bool) {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m, true
+	}
+	rv := reflect.ValueOf(v)
+	filterType := reflect.TypeOf(map[string]interface{}(nil))
+	if rv.Kind() == reflect.Map && rv.Type().ConvertibleTo(filterType) {
+		return rv.Convert(filterType).Interface().(map[string]interface{}), true
+	}
+	return nil, false
+}
+
+// isNumber reports whether v is a Go or JSON number
+func isNumber(v interface{}) bool {
+	if _, ok := v.(json.Number); ok {
+		return true
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isScalar reports whether v is a string, number or bool
+func isScalar(v interface{}) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.String, reflect.Bool:
+		return true
+	}
+	return isNumber(v)
+}
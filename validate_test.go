@@ -0,0 +1,226 @@
+package chromaclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kevensen/go-chroma-client/where"
+)
+
+func TestAddValidation(t *testing.T) {
+	client := NewClient(WithBaseURL("http://127.0.0.1:0"))
+
+	tests := []struct {
+		name string
+		req  AddEmbedding
+		want string
+	}{
+		{
+			name: "no embeddings or documents",
+			req:  AddEmbedding{IDs: []string{"id1"}, Metadatas: []map[string]interface{}{{"k": "v"}}},
+			want: "one of Embeddings or Documents is required",
+		},
+		{
+			name: "embeddings length mismatch",
+			req:  AddEmbedding{IDs: []string{"id1", "id2"}, Embeddings: [][]float64{{0.1}}},
+			want: "Embeddings has 1 entries but IDs has 2",
+		},
+		{
+			name: "documents length mismatch",
+			req:  AddEmbedding{IDs: []string{"id1"}, Documents: []string{"doc1", "doc2"}},
+			want: "Documents has 2 entries but IDs has 1",
+		},
+		{
+			name: "metadatas length mismatch",
+			req: AddEmbedding{
+				IDs:       []string{"id1", "id2"},
+				Documents: []string{"doc1", "doc2"},
+				Metadatas: []map[string]interface{}{{"k": "v"}},
+			},
+			want: "Metadatas has 1 entries but IDs has 2",
+		},
+		{
+			name: "uris length mismatch",
+			req: AddEmbedding{
+				IDs:        []string{"id1", "id2"},
+				Embeddings: [][]float64{{0.1}, {0.2}},
+				Uris:       []string{"s3://bucket/a.png"},
+			},
+			want: "Uris has 1 entries but IDs has 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, call := range map[string]func() error{
+				"Add":    func() error { return client.Add(context.Background(), "col-123", tt.req, "", "") },
+				"Upsert": func() error { return client.Upsert(context.Background(), "col-123", tt.req, "", "") },
+			} {
+				err := call()
+				if err == nil {
+					t.Fatalf("%s: expected error, got nil", name)
+				}
+				if !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("%s: expected error containing %q, got %v", name, tt.want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAddValidationDocumentsOnly(t *testing.T) {
+	err := validateAddEmbedding(AddEmbedding{
+		IDs:       []string{"id1", "id2"},
+		Documents: []string{"doc1", "doc2"},
+	})
+	if err != nil {
+		t.Errorf("Expected documents without embeddings to be valid, got %v", err)
+	}
+}
+
+func TestConfigurationSpaceValidation(t *testing.T) {
+	client := NewClient(WithBaseURL("http://127.0.0.1:0"))
+	typo := Space("cosign")
+
+	err := client.UpdateCollection(context.Background(), "col-123", UpdateCollection{
+		NewConfiguration: &CollectionConfiguration{Spann: &SpannConfiguration{Space: &typo}},
+	}, "", "")
+	if err == nil || !strings.Contains(err.Error(), `spann space "cosign"`) {
+		t.Errorf("UpdateCollection: expected invalid space error, got %v", err)
+	}
+
+	_, err = client.CreateCollection(context.Background(), CreateCollection{
+		Name:          "test_collection",
+		Configuration: &CollectionConfiguration{Hnsw: &HnswConfiguration{Space: &typo}},
+	}, "", "")
+	if err == nil || !strings.Contains(err.Error(), `hnsw space "cosign"`) {
+		t.Errorf("CreateCollection: expected invalid space error, got %v", err)
+	}
+
+	// Without a space the request reaches the transport, which fails to connect
+	_, err = client.CreateCollection(context.Background(), CreateCollection{
+		Name:          "test_collection",
+		Configuration: &CollectionConfiguration{Hnsw: &HnswConfiguration{}},
+	}, "", "")
+	if err == nil || strings.Contains(err.Error(), "invalid configuration") {
+		t.Errorf("CreateCollection: expected connection error, got %v", err)
+	}
+}
+
+func TestValidateWhere(t *testing.T) {
+	valid := []map[string]interface{}{
+		{"category": "tech"},
+		where.And(where.Eq("category", "tech"), where.Gt("price", 10), where.In("topic", []string{"a", "b"})),
+		where.Or(where.Lte("score", 0.5), where.Nin("rank", []int{1, 2})),
+		{"price": map[string]interface{}{"$custom": []string{"x"}}},
+	}
+	for _, w := range valid {
+		if err := ValidateWhere(w); err != nil {
+			t.Errorf("Expected %v to be valid, got %v", w, err)
+		}
+	}
+
+	tests := []struct {
+		where map[string]interface{}
+		want  string
+	}{
+		{where.Gt("price", "10"), "$gt on \"price\" requires a number, got string"},
+		{map[string]interface{}{"topic": map[string]interface{}{"$in": "a"}}, "$in on \"topic\" requires a list, got string"},
+		{map[string]interface{}{"topic": map[string]interface{}{"$nin": []interface{}{"a", nil}}}, "$nin on \"topic\": value 1"},
+		{map[string]interface{}{"$and": where.Eq("a", 1)}, "$and requires a list of filters"},
+		{map[string]interface{}{"$or": []interface{}{"a"}}, "$or operand 0 must be a filter"},
+		{where.And(where.Eq("a", 1), where.Lt("b", true)), "$lt on \"b\" requires a number, got bool"},
+		{map[string]interface{}{"tags": []string{"a"}}, "\"tags\" must be compared to a string, number or bool"},
+	}
+	for _, tt := range tests {
+		err := ValidateWhere(tt.where)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ValidateWhere(%v): expected error containing %q, got %v", tt.where, tt.want, err)
+		}
+		if err != nil && !strings.HasPrefix(err.Error(), "invalid where: ") {
+			t.Errorf("Expected invalid where prefix, got %v", err)
+		}
+	}
+}
+
+func TestWithWhereValidation(t *testing.T) {
+	client := NewClient(WithBaseURL("http://127.0.0.1:0"), WithWhereValidation(true))
+	bad := where.Gte("price", "cheap")
+
+	if _, err := client.Get(context.Background(), "c", GetEmbedding{Where: bad}, "", ""); err == nil || !strings.Contains(err.Error(), "invalid where") {
+		t.Errorf("Expected Get to reject the filter, got %v", err)
+	}
+	if _, err := client.Query(context.Background(), "c", QueryEmbedding{QueryEmbeddings: [][]float64{{1}}, Where: bad}, "", ""); err == nil || !strings.Contains(err.Error(), "invalid where") {
+		t.Errorf("Expected Query to reject the filter, got %v", err)
+	}
+	if err := client.Delete(context.Background(), "c", DeleteEmbedding{Where: bad}, "", ""); err == nil || !strings.Contains(err.Error(), "invalid where") {
+		t.Errorf("Expected Delete to reject the filter, got %v", err)
+	}
+	if _, err := client.CountByFilter(context.Background(), "c", bad, "", ""); err == nil || !strings.Contains(err.Error(), "invalid where") {
+		t.Errorf("Expected CountByFilter to reject the filter, got %v", err)
+	}
+	if _, err := client.Get32(context.Background(), "c", GetEmbedding{Where: bad}, "", ""); err == nil || !strings.Contains(err.Error(), "invalid where") {
+		t.Errorf("Expected Get32 to reject the filter, got %v", err)
+	}
+
+	client = NewClient(WithBaseURL("http://127.0.0.1:0"))
+	if _, err := client.Get(context.Background(), "c", GetEmbedding{Where: bad}, "", ""); err == nil || strings.Contains(err.Error(), "invalid where") {
+		t.Errorf("Expected the filter to reach the server without validation, got %v", err)
+	}
+}
+
+func TestDuplicateIDPolicy(t *testing.T) {
+	var received []AddEmbedding
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AddEmbedding
+		json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	req := AddEmbedding{
+		IDs:       []string{"a", "b", "a", "c", "b", "a"},
+		Documents: []string{"1", "2", "3", "4", "5", "6"},
+	}
+
+	client := NewClient(WithBaseURL(server.URL))
+	err := client.Add(context.Background(), "col-123", req, "", "")
+	if err == nil || !strings.Contains(err.Error(), `duplicate IDs "a" at 0, 2, 5; "b" at 1, 4`) {
+		t.Errorf("Expected duplicate IDs with positions, got %v", err)
+	}
+	if err := client.AddBatched(context.Background(), "col-123", req, 2, "", ""); err == nil {
+		t.Error("Expected AddBatched to reject duplicates across batches")
+	}
+	if len(received) != 0 {
+		t.Fatalf("Expected nothing to be sent, got %d requests", len(received))
+	}
+
+	client = NewClient(WithBaseURL(server.URL), WithDuplicateIDPolicy(KeepLastDuplicateID))
+	if err := client.Add(context.Background(), "col-123", req, "", ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(received))
+	}
+	if got := received[0]; !slices.Equal(got.IDs, []string{"c", "b", "a"}) || !slices.Equal(got.Documents, []string{"4", "5", "6"}) {
+		t.Errorf("Expected the last row of each ID, got %v %v", got.IDs, got.Documents)
+	}
+
+	received = nil
+	err = client.Add32(context.Background(), "col-123", AddEmbedding32{
+		IDs:        []string{"a", "a"},
+		Embeddings: [][]float32{{1}, {2}},
+	}, "", "")
+	if err != nil {
+		t.Fatalf("Add32() error = %v", err)
+	}
+	if got := received[0]; len(got.IDs) != 1 || got.Embeddings[0][0] != 2 {
+		t.Errorf("Expected Add32 to keep the last row, got %+v", got)
+	}
+}
@@ -0,0 +1,110 @@
+package chromaclient
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrVersionTooOld is returned by RequireVersion when the server is older
+// than the required version
+var ErrVersionTooOld = errors.New("chroma server version too old")
+
+// RequireVersion returns an error unless the server runs minVersion or later,
+// e.g. to gate features such as forking or SPANN indexes. Versions are
+// compared as semantic versions: a leading "v" and build metadata are
+// ignored, and a pre-release such as 1.0.0-rc1 is older than 1.0.0. A server
+// that is too old is reported with ErrVersionTooOld.
+func (c *Client) RequireVersion(ctx context.Context, minVersion string) error {
+	required, ok := parseVersion(minVersion)
+	if !ok {
+		return fmt.Errorf("invalid minimum version %q", minVersion)
+	}
+	version, err := c.Version(ctx)
+	if err != nil {
+		return err
+	}
+	server, ok := parseVersion(version)
+	if !ok {
+		return fmt.Errorf("cannot parse server version %q", version)
+	}
+	if server.compare(required) < 0 {
+		return fmt.Errorf("%w: server runs %s, %s or later is required", ErrVersionTooOld, version, minVersion)
+	}
+	return nil
+}
+
+// semver is a parsed semantic version
+type semver struct {
+	core       [3]int
+	prerelease string
+}
+
+// parseVersion parses MAJOR[.MINOR[.PATCH]] with an optional "v" prefix,
+// pre-release and build metadata. Missing minor and patch numbers are zero.
+func parseVersion(s string) (semver, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, prerelease, _ := strings.Cut(s, "-")
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return semver{}, false
+	}
+	v := semver{prerelease: prerelease}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		v.core[i] = n
+	}
+	return v, true
+}
+
+// compare returns -1, 0 or 1 as v is older than, equal to or newer than w
+func (v semver) compare(w semver) int {
+	for i := range v.core {
+		if c := cmp.Compare(v.core[i], w.core[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case v.prerelease == w.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case w.prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.prerelease, w.prerelease)
+}
+
+// comparePrerelease compares dot-separated pre-release identifiers:
+// numeric identifiers numerically and below alphanumeric ones, which compare
+// as strings
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if c := cmp.Compare(an, bn); c != 0 {
+				return c
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
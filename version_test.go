@@ -0,0 +1,52 @@
+package chromaclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireVersion(t *testing.T) {
+	version := "1.0.20"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`"` + version + `"`))
+	}))
+	defer server.Close()
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	for _, min := range []string{"1.0.20", "1.0", "v0.6.3", "1.0.20-rc1", "1"} {
+		if err := client.RequireVersion(ctx, min); err != nil {
+			t.Errorf("RequireVersion(%q) error = %v", min, err)
+		}
+	}
+
+	err := client.RequireVersion(ctx, "1.1.0")
+	if !errors.Is(err, ErrVersionTooOld) || !strings.Contains(err.Error(), "server runs 1.0.20, 1.1.0 or later is required") {
+		t.Errorf("Expected ErrVersionTooOld, got %v", err)
+	}
+
+	if err := client.RequireVersion(ctx, "latest"); err == nil || !strings.Contains(err.Error(), `invalid minimum version "latest"`) {
+		t.Errorf("Expected invalid minimum version error, got %v", err)
+	}
+
+	version = "1.1.0-rc.2"
+	if err := client.RequireVersion(ctx, "1.1.0"); !errors.Is(err, ErrVersionTooOld) {
+		t.Errorf("Expected a pre-release to be older than its release, got %v", err)
+	}
+	if err := client.RequireVersion(ctx, "1.1.0-rc.10"); !errors.Is(err, ErrVersionTooOld) {
+		t.Errorf("Expected rc.2 to be older than rc.10, got %v", err)
+	}
+	if err := client.RequireVersion(ctx, "1.1.0-rc.1"); err != nil {
+		t.Errorf("Expected rc.2 to satisfy rc.1, got %v", err)
+	}
+
+	version = "dev-build"
+	if err := client.RequireVersion(ctx, "1.0.0"); err == nil || !strings.Contains(err.Error(), `cannot parse server version "dev-build"`) {
+		t.Errorf("Expected parse error, got %v", err)
+	}
+}
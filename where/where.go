@@ -0,0 +1,94 @@
+// Package where builds ChromaDB metadata filters for the Where field of
+// GetEmbedding, QueryEmbedding and DeleteEmbedding.
+//
+//	filter := where.And(
+//		where.Eq("category", "tech"),
+//		where.Gt("price", 10),
+//		where.In("topic", []string{"a", "b"}),
+//	)
+//
+// A Filter is a map[string]interface{}, so it can be assigned to those fields
+// directly.
+package where
+
+// Filter is a ChromaDB metadata filter
+type Filter map[string]interface{}
+
+// Value is a metadata value type accepted by In and Nin
+type Value interface {
+	~string | ~int | ~int32 | ~int64 | ~float32 | ~float64 | ~bool
+}
+
+// Eq matches records whose key equals value
+func Eq(key string, value interface{}) Filter {
+	return op(key, "$eq", value)
+}
+
+// Ne matches records whose key does not equal value
+func Ne(key string, value interface{}) Filter {
+	return op(key, "$ne", value)
+}
+
+// Gt matches records whose key is greater than value
+func Gt(key string, value interface{}) Filter {
+	return op(key, "$gt", value)
+}
+
+// Gte matches records whose key is greater than or equal to value
+func Gte(key string, value interface{}) Filter {
+	return op(key, "$gte", value)
+}
+
+// Lt matches records whose key is less than value
+func Lt(key string, value interface{}) Filter {
+	return op(key, "$lt", value)
+}
+
+// Lte matches records whose key is less than or equal to value
+func Lte(key string, value interface{}) Filter {
+	return op(key, "$lte", value)
+}
+
+// In matches records whose key is one of values
+func In[T Value](key string, values []T) Filter {
+	return op(key, "$in", values)
+}
+
+// Nin matches records whose key is none of values
+func Nin[T Value](key string, values []T) Filter {
+	return op(key, "$nin", values)
+}
+
+// And matches records that match every filter. ChromaDB requires at least
+// two operands, so a single filter is returned as is and no filters yield
+// nil.
+func And(filters ...Filter) Filter {
+	return logical("$and", filters)
+}
+
+// Or matches records that match any filter. Like And, a single filter is
+// returned as is and no filters yield nil.
+func Or(filters ...Filter) Filter {
+	return logical("$or", filters)
+}
+
+func op(key, operator string, value interface{}) Filter {
+	return Filter{key: map[string]interface{}{operator: value}}
+}
+
+func logical(operator string, filters []Filter) Filter {
+	var operands []interface{}
+	for _, f := range filters {
+		if len(f) > 0 {
+			operands = append(operands, f)
+		}
+	}
+
+	switch len(operands) {
+	case 0:
+		return nil
+	case 1:
+		return operands[0].(Filter)
+	}
+	return Filter{operator: operands}
+}
@@ -0,0 +1,69 @@
+package where
+
+import (
+	"encoding/json"
+	"testing"
+
+	chromaclient "github.com/kevensen/go-chroma-client"
+)
+
+func TestFilterJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{"eq", Eq("category", "tech"), `{"category":{"$eq":"tech"}}`},
+		{"ne", Ne("category", "tech"), `{"category":{"$ne":"tech"}}`},
+		{"gt", Gt("price", 10), `{"price":{"$gt":10}}`},
+		{"gte", Gte("price", 10.5), `{"price":{"$gte":10.5}}`},
+		{"lt", Lt("price", 10), `{"price":{"$lt":10}}`},
+		{"lte", Lte("price", 10), `{"price":{"$lte":10}}`},
+		{"in", In("topic", []string{"a", "b"}), `{"topic":{"$in":["a","b"]}}`},
+		{"nin", Nin("year", []int{2023, 2024}), `{"year":{"$nin":[2023,2024]}}`},
+		{
+			"and",
+			And(Eq("category", "tech"), Gt("price", 10)),
+			`{"$and":[{"category":{"$eq":"tech"}},{"price":{"$gt":10}}]}`,
+		},
+		{
+			"nested",
+			Or(Eq("a", true), And(Lt("b", 1), Gte("c", 2))),
+			`{"$or":[{"a":{"$eq":true}},{"$and":[{"b":{"$lt":1}},{"c":{"$gte":2}}]}]}`,
+		},
+		{"single and", And(Eq("a", 1)), `{"a":{"$eq":1}}`},
+		{"empty and", And(), `null`},
+		{"skips empty", Or(And(), Eq("a", 1), nil), `{"a":{"$eq":1}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.filter)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFilterAssignableToWhere(t *testing.T) {
+	req := chromaclient.QueryEmbedding{
+		QueryEmbeddings: [][]float64{{0.1}},
+		Where:           And(Eq("category", "tech"), Gt("price", 10)),
+	}
+	get := chromaclient.GetEmbedding{Where: Eq("category", "tech")}
+
+	got, err := json.Marshal(get)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(got) != `{"where":{"category":{"$eq":"tech"}}}` {
+		t.Errorf("Unexpected get request %s", got)
+	}
+	if _, ok := req.Where["$and"]; !ok {
+		t.Errorf("Expected $and in query where, got %v", req.Where)
+	}
+}